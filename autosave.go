@@ -0,0 +1,176 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"gopkg.in/mgo.v2/bson"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Autosave is the unsaved state of the write page for a user and a post.
+// New posts have no Post.
+type Autosave struct {
+	Id      bson.ObjectId `bson:"_id,omitempty" json:"-"`
+	User    bson.ObjectId `bson:"_user" json:"-"`
+	Post    bson.ObjectId `bson:"_post,omitempty" json:"post,omitempty"`
+	Title   string        `json:"title"`
+	Source  string        `json:"source"`
+	Excerpt string        `json:"excerpt"`
+	Tags    string        `json:"tags"`
+	Date    time.Time     `json:"date"`
+	// Started is when the write page it came from was opened, so an
+	// autosave sent after that page was submitted can be told apart.
+	Started time.Time `json:"-"`
+}
+
+func autosaveKey(user bson.ObjectId, post bson.ObjectId) bson.M {
+	if post == "" {
+		return bson.M{"_user": user, "_post": bson.M{"$exists": false}}
+	}
+	return bson.M{"_user": user, "_post": post}
+}
+
+// Store replaces the user's autosave for the post.
+func (a *Autosave) Store() error {
+	a.Date = time.Now().UTC()
+	localsession := session.Copy()
+	defer localsession.Close()
+	info, err := localsession.DB(database).C("autosaves").Upsert(autosaveKey(a.User, a.Post), a)
+	if err == nil && info.UpsertedId != nil {
+		a.Id = info.UpsertedId.(bson.ObjectId)
+	}
+	return err
+}
+
+func GetAutosave(user bson.ObjectId, post bson.ObjectId) (Autosave, error) {
+	localsession := session.Copy()
+	defer localsession.Close()
+	a := Autosave{}
+	err := localsession.DB(database).C("autosaves").Find(autosaveKey(user, post)).One(&a)
+	return a, err
+}
+
+func DiscardAutosave(user bson.ObjectId, post bson.ObjectId) error {
+	localsession := session.Copy()
+	defer localsession.Close()
+	_, err := localsession.DB(database).C("autosaves").RemoveAll(autosaveKey(user, post))
+	return err
+}
+
+// autosaveFor returns the autosave to offer when the write page opens, if
+// there is one newer than the saved post.
+func autosaveFor(user bson.ObjectId, post *BlogPost) *Autosave {
+	id := bson.ObjectId("")
+	if post != nil {
+		id = post.Id
+	}
+	a, err := GetAutosave(user, id)
+	if err != nil || a.submitted(post) {
+		return nil
+	}
+	return &a
+}
+
+// submitted reports whether what the autosave holds was saved since: the
+// post was saved after it, or the user saved the post or created a new one
+// after opening the page it came from.
+func (a Autosave) submitted(post *BlogPost) bool {
+	if post != nil && !a.Date.After(post.DateEdited) {
+		return true
+	}
+	if a.Started.IsZero() {
+		return false
+	}
+	if post != nil {
+		return post.EditedBy == a.User && post.DateEdited.After(a.Started)
+	}
+	latest, err := latestBlogByAuthor(a.User)
+	return err == nil && !latest.Id.Time().Before(a.Started)
+}
+
+// latestBlogByAuthor returns the post the user created last, trashed or
+// not.
+func latestBlogByAuthor(user bson.ObjectId) (BlogPost, error) {
+	localsession := session.Copy()
+	defer localsession.Close()
+	post := BlogPost{}
+	err := localsession.DB(database).C("blogs").Find(bson.M{"_author": user}).Sort("-_id").One(&post)
+	return post, err
+}
+
+func writeJson(w http.ResponseWriter, code int, v interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	return json.NewEncoder(w).Encode(v)
+}
+
+// autosavePost reads the post id of an autosave request. New posts have
+// none.
+func autosavePost(req *http.Request) (bson.ObjectId, bool) {
+	id := req.FormValue("post")
+	if id == "" {
+		return "", true
+	}
+	if !bson.IsObjectIdHex(id) {
+		return "", false
+	}
+	return bson.ObjectIdHex(id), true
+}
+
+func AutosaveHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
+	if ctx.User == nil || (!ctx.User.IsBlogAuthor && !ctx.User.IsAdmin) {
+		return writeJson(w, http.StatusUnauthorized, map[string]string{"error": "not logged in"})
+	}
+	post, ok := autosavePost(req)
+	if !ok {
+		return writeJson(w, http.StatusBadRequest, map[string]string{"error": "bad post id"})
+	}
+	if post != "" {
+		saved, err := GetBlogPostWithId(post)
+		if err != nil || !saved.CanEdit(*ctx.User) {
+			return writeJson(w, http.StatusNotFound, map[string]string{"error": "no such post"})
+		}
+	}
+
+	if req.Method == "GET" {
+		a, err := GetAutosave(ctx.User.Id, post)
+		if err != nil {
+			return writeJson(w, http.StatusNotFound, map[string]string{"error": "no autosave"})
+		}
+		return writeJson(w, http.StatusOK, a)
+	}
+
+	a := Autosave{
+		User:    ctx.User.Id,
+		Post:    post,
+		Title:   req.FormValue("title"),
+		Source:  req.FormValue("source"),
+		Excerpt: req.FormValue("excerpt"),
+		Tags:    req.FormValue("tags"),
+	}
+	if started, err := strconv.ParseInt(req.FormValue("started"), 10, 64); err == nil {
+		a.Started = time.Unix(started, 0).UTC()
+	}
+	if err = a.Store(); err != nil {
+		return writeJson(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return writeJson(w, http.StatusOK, a)
+}
+
+func AutosaveDiscardHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
+	if ctx.User == nil {
+		return writeJson(w, http.StatusUnauthorized, map[string]string{"error": "not logged in"})
+	}
+	post, ok := autosavePost(req)
+	if !ok {
+		return writeJson(w, http.StatusBadRequest, map[string]string{"error": "bad post id"})
+	}
+	if err = DiscardAutosave(ctx.User.Id, post); err != nil {
+		return writeJson(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return writeJson(w, http.StatusOK, map[string]bool{"discarded": true})
+}
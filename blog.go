@@ -5,9 +5,9 @@ package main
 
 import (
 	"crypto/sha1"
+	"errors"
 	"fmt"
-	"github.com/microcosm-cc/bluemonday"
-	"github.com/russross/blackfriday"
+	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 	"html/template"
 	"image"
@@ -15,7 +15,6 @@ import (
 	_ "image/jpeg"
 	_ "image/png"
 	"io/ioutil"
-	"math/rand"
 	"mime/multipart"
 	"net/http"
 	"os"
@@ -25,19 +24,39 @@ import (
 )
 
 type BlogPost struct {
-	Id         bson.ObjectId `bson:"_id,omitempty"`
-	Title      string
-	Slug       string
-	Source     template.HTML
-	Content    template.HTML
-	Date       time.Time
-	Author     bson.ObjectId `bson:"_author"`
-	Edited     bool
-	DateEdited time.Time
-	EditedBy   bson.ObjectId `bson:"_editor"`
-	Images     []string
-	Width      int
-	Published  bool
+	Id             bson.ObjectId `bson:"_id,omitempty"`
+	Title          string
+	Slug           string
+	Excerpt        string
+	Source         template.HTML
+	Content        template.HTML
+	WordCount      int
+	ReadingTime    int
+	Toc            []Heading
+	Date           time.Time
+	Author         bson.ObjectId `bson:"_author"`
+	Edited         bool
+	DateEdited     time.Time
+	EditedBy       bson.ObjectId `bson:"_editor"`
+	Images         []string
+	Tags           []string
+	Related        []bson.ObjectId
+	Width          int
+	Featured       bool
+	Pinned         bool      `bson:",omitempty"`
+	PinOrder       int       `bson:",omitempty"`
+	PinExpires     time.Time `bson:",omitempty"`
+	Version        int
+	Published      bool
+	Unlisted       bool
+	State          string
+	Transitions    []Transition
+	ReviewComments []ReviewComment
+	Deleted        bool
+	DateDeleted    time.Time
+	DeletedBy      bson.ObjectId `bson:"_deleter,omitempty"`
+	GitPath        string        `bson:",omitempty"`
+	Commit         string        `bson:",omitempty"`
 }
 
 type SubImager interface {
@@ -45,18 +64,112 @@ type SubImager interface {
 }
 
 func (post BlogPost) Summary() template.HTML {
-	bytes := []byte(string(post.Source))
-	if len(bytes) < 256 {
-		return template.HTML(bluemonday.StrictPolicy().SanitizeBytes(blackfriday.MarkdownCommon(bytes)))
+	return template.HTML(template.HTMLEscapeString(post.PlainSummary()))
+}
+
+// PlainSummary is the text of the excerpt of the post, of the part before
+// MoreMarker, or of its start. Shortcodes are not run for it.
+func (post BlogPost) PlainSummary() string {
+	switch {
+	case strings.TrimSpace(post.Excerpt) != "":
+		return RenderMarkdownText(post.Excerpt)
+	case strings.Contains(string(post.Source), MoreMarker):
+		return RenderMarkdownText(strings.SplitN(string(post.Source), MoreMarker, 2)[0])
+	case post.Content != "":
+		return TruncateWords(PlainText(post.Content), SummaryLength)
+	default:
+		return TruncateWords(RenderMarkdownText(string(post.Source)), SummaryLength)
 	}
-	trimmed := bytes[0:256]
-	return template.HTML(bluemonday.StrictPolicy().SanitizeBytes(blackfriday.MarkdownCommon(trimmed)))
 }
 
-func (post BlogPost) Store() {
+// Render renders Source into Content and refreshes everything derived from
+// it.
+func (post *BlogPost) Render() {
+	post.Content, post.Toc = RenderMarkdownWithToc(string(post.Source))
+	post.WordCount = CountWords(post.Content)
+	post.ReadingTime = ReadingTime(post.WordCount)
+}
+
+func (post BlogPost) ShowToc() bool {
+	return len(post.Toc) >= TocMinHeadings && post.ReadingTime >= TocMinMinutes
+}
+
+// ErrConflict is returned by Store when someone else saved the post after
+// it was loaded.
+var ErrConflict = errors.New("the post was changed by someone else")
+
+// Store saves the post if it is still at the version it was loaded at, and
+// moves it to the next version. It returns ErrConflict otherwise.
+func (post *BlogPost) Store() error {
+	post.Render()
 	localsession := session.Copy()
 	defer localsession.Close()
-	localsession.DB(database).C("blogs").Update(bson.M{"_id": post.Id}, post)
+
+	// Posts from before versioning have no version field at all.
+	version := interface{}(post.Version)
+	if post.Version == 0 {
+		version = bson.M{"$in": []interface{}{0, nil}}
+	}
+	post.Version++
+	err := localsession.DB(database).C("blogs").Update(bson.M{"_id": post.Id, "version": version}, post)
+	if err == mgo.ErrNotFound {
+		post.Version--
+		return ErrConflict
+	} else if err != nil {
+		post.Version--
+		return err
+	}
+	ScheduleRelatedRefresh()
+	return nil
+}
+
+// update applies a partial update to the post matching selector, which
+// defaults to its id, and moves it to the next version like Store does, so
+// an edit loaded before it gets ErrConflict instead of undoing it.
+func (post *BlogPost) update(selector bson.M, change bson.M) error {
+	if selector == nil {
+		selector = bson.M{}
+	}
+	selector["_id"] = post.Id
+	change["$inc"] = bson.M{"version": 1}
+	localsession := session.Copy()
+	defer localsession.Close()
+	if err := localsession.DB(database).C("blogs").Update(selector, change); err != nil {
+		return err
+	}
+	post.Version++
+	return nil
+}
+
+// DefaultImage is the header image of posts without one, unless the site
+// configures its own.
+const DefaultImage = "/assets/img/bg_2048.jpg"
+
+func (post BlogPost) HasImage() bool {
+	return len(post.Images) > 2
+}
+
+// HeaderImage is the path of the image shown behind the post.
+func (post BlogPost) HeaderImage() string {
+	if post.HasImage() {
+		return post.Images[2]
+	}
+	return DefaultHeaderImage()
+}
+
+// DefaultHeaderImage is the path of the image shown behind posts without
+// one of their own.
+func DefaultHeaderImage() string {
+	if config.Site.DefaultImage != "" {
+		return config.Site.DefaultImage
+	}
+	return DefaultImage
+}
+
+// HeaderImageUrl is the absolute URL of HeaderImage, for other sites to
+// link to.
+func (post BlogPost) HeaderImageUrl() string {
+	return absoluteUrl(post.HeaderImage())
 }
 
 func (post BlogPost) CanEdit(user User) bool {
@@ -87,11 +200,21 @@ func (post BlogPost) GetEditorAsUser() User {
 	return user
 }
 
+// live restricts a query on blogs to posts that are not in the trash.
+func live(query bson.M) bson.M {
+	query["deleted"] = bson.M{"$ne": true}
+	return query
+}
+
+func blogImageFolder(id bson.ObjectId) string {
+	return "./static/img/blog/" + id.Hex() + "/"
+}
+
 func GetBlogPostWithId(id bson.ObjectId) (BlogPost, error) {
 	localsession := session.Copy()
 	defer localsession.Close()
 	post := BlogPost{}
-	err := localsession.DB(database).C("blogs").Find(bson.M{"_id": id}).One(&post)
+	err := localsession.DB(database).C("blogs").Find(live(bson.M{"_id": id})).One(&post)
 	if err != nil {
 		return post, err
 	}
@@ -102,25 +225,49 @@ func GetBlogPostWithSlug(slug string) (BlogPost, error) {
 	localsession := session.Copy()
 	defer localsession.Close()
 	post := BlogPost{}
-	err := localsession.DB(database).C("blogs").Find(bson.M{"slug": slug}).One(&post)
+	err := localsession.DB(database).C("blogs").Find(live(bson.M{"slug": slug})).One(&post)
 	if err != nil {
 		return post, err
 	}
 	return post, nil
 }
 
-func GetBlogsByAuthor(user *User) (posts []BlogPost, err error) {
+func GetBlogsByAuthor(user *User, count int, page int) (posts []BlogPost, err error) {
 	localsession := session.Copy()
 	defer localsession.Close()
-	err = localsession.DB(database).C("blogs").Find(bson.M{"_author": user.Id, "published": true}).Sort("-date").All(&posts)
+	offset := 0
+	if page > 1 {
+		offset = (page - 1) * count
+	}
+	err = localsession.DB(database).C("blogs").Find(listed(bson.M{"_author": user.Id, "published": true})).Sort("-date").Skip(offset).Limit(count).All(&posts)
 	return
 }
 
+// GetAllBlogsByAuthor returns the newest posts of the user, drafts and
+// unlisted posts included.
+func GetAllBlogsByAuthor(user *User, count int) []BlogPost {
+	localsession := session.Copy()
+	defer localsession.Close()
+	blogs := []BlogPost{}
+	localsession.DB(database).C("blogs").Find(live(bson.M{"_author": user.Id})).Sort("-date").Limit(count).All(&blogs)
+	return blogs
+}
+
+func CountBlogsByAuthor(user *User) int {
+	localsession := session.Copy()
+	defer localsession.Close()
+	count, err := localsession.DB(database).C("blogs").Find(listed(bson.M{"_author": user.Id, "published": true})).Count()
+	if err != nil {
+		return 0
+	}
+	return count
+}
+
 func GetBlogPost(slug string) (BlogPost, error) {
 	localsession := session.Copy()
 	defer localsession.Close()
 	post := BlogPost{}
-	err := localsession.DB(database).C("blogs").Find(bson.M{"slug": slug}).One(&post)
+	err := localsession.DB(database).C("blogs").Find(live(bson.M{"slug": slug})).One(&post)
 	if err != nil {
 		return post, err
 	}
@@ -130,7 +277,7 @@ func GetBlogPost(slug string) (BlogPost, error) {
 func CountBlogs() int {
 	localsession := session.Copy()
 	defer localsession.Close()
-	count, err := localsession.DB(database).C("blogs").Find(bson.M{"published": true}).Count()
+	count, err := localsession.DB(database).C("blogs").Find(listed(bson.M{"published": true})).Count()
 	if err != nil {
 		return 0
 	}
@@ -145,7 +292,7 @@ func GetBlogs(count int, page int) []BlogPost {
 		offset = (page - 1) * count
 	}
 	blogs := []BlogPost{}
-	localsession.DB(database).C("blogs").Find(bson.M{"published": true}).Sort("-date").Skip(offset).Limit(count).All(&blogs)
+	localsession.DB(database).C("blogs").Find(listed(bson.M{"published": true})).Sort("-date").Skip(offset).Limit(count).All(&blogs)
 	return blogs
 }
 
@@ -157,72 +304,90 @@ func GetBlogsCrono(count int, page int) []BlogPost {
 		offset = (page - 1) * count
 	}
 	blogs := []BlogPost{}
-	localsession.DB(database).C("blogs").Find(bson.M{"published": true}).Sort("date").Skip(offset).Limit(count).All(&blogs)
+	localsession.DB(database).C("blogs").Find(listed(bson.M{"published": true})).Sort("date").Skip(offset).Limit(count).All(&blogs)
 	return blogs
 }
 
-func CreateBlog(img multipart.File, imageHeader *multipart.FileHeader, w http.ResponseWriter, req *http.Request, ctx *Context) (BlogPost, error) {
-	blog := BlogPost{}
-
-	id := bson.NewObjectId()
-	title := req.FormValue("title")
-	source := req.FormValue("source")
-	content := req.FormValue("content")
-	date := time.Now().UTC()
-	author := ctx.User.Id
-	editor := ctx.User.Id
-	editdate := time.Now().UTC()
-
-	imgContent, err := ioutil.ReadAll(img)
-	if err != nil {
-		debug.PrintStack()
-		log.Error(err.Error())
-		return blog, err
-	}
-	defer img.Close()
-
+// ProcessBlogImage stores an uploaded header image for the post with the
+// given id along with its re-encoded JPEG versions, and returns their URLs.
+func ProcessBlogImage(id bson.ObjectId, filename string, imgContent []byte) ([]string, error) {
 	imgsha1 := fmt.Sprintf("%x", sha1.Sum(imgContent))
 
-	imageFolder := "./static/img/blog/" + id.Hex() + "/"
+	imageFolder := blogImageFolder(id)
 
 	os.MkdirAll(imageFolder, 0777)
 
-	err = ioutil.WriteFile(imageFolder+imgsha1+imageHeader.Filename, imgContent, 0664)
+	err := ioutil.WriteFile(imageFolder+imgsha1+filename, imgContent, 0664)
 	if err != nil {
 		debug.PrintStack()
 		log.Error(err.Error())
-		return blog, err
+		return nil, err
 	}
 
-	diskimg, err := os.Open(imageFolder + imgsha1 + imageHeader.Filename)
+	diskimg, err := os.Open(imageFolder + imgsha1 + filename)
 	if err != nil {
 		debug.PrintStack()
 		log.Error(err.Error())
-		return blog, err
+		return nil, err
 	}
+	defer diskimg.Close()
 
 	srcimage, _, err := image.Decode(diskimg)
 	if err != nil {
 		debug.PrintStack()
 		log.Error(err.Error())
-		return blog, err
+		return nil, err
 	}
 
 	WriteJpegImageToFile(imageFolder+imgsha1+".original.100.jpg", 100, srcimage)
 	WriteJpegImageToFile(imageFolder+imgsha1+".original.85.jpg", 85, srcimage)
 	WriteJpegImageToFile(imageFolder+imgsha1+".original.65.jpg", 65, srcimage)
 
-	images := []string{
-		"/assets/img/blog/" + id.Hex() + "/" + imgsha1 + imageHeader.Filename,
+	return []string{
+		"/assets/img/blog/" + id.Hex() + "/" + imgsha1 + filename,
 		"/assets/img/blog/" + id.Hex() + "/" + imgsha1 + ".original.100.jpg",
 		"/assets/img/blog/" + id.Hex() + "/" + imgsha1 + ".original.85.jpg",
 		"/assets/img/blog/" + id.Hex() + "/" + imgsha1 + ".original.65.jpg",
+	}, nil
+}
+
+func CreateBlog(img multipart.File, imageHeader *multipart.FileHeader, w http.ResponseWriter, req *http.Request, ctx *Context) (BlogPost, error) {
+	blog := BlogPost{}
+
+	id := bson.NewObjectId()
+	title := req.FormValue("title")
+	source := req.FormValue("source")
+	excerpt := req.FormValue("excerpt")
+	unlisted := req.FormValue("unlisted") != ""
+	tags := ParseTags(req.FormValue("tags"))
+	date := time.Now().UTC()
+	author := ctx.User.Id
+	editor := ctx.User.Id
+	editdate := time.Now().UTC()
+
+	// The header image is optional, posts without one use DefaultImage.
+	var images []string
+	if img != nil {
+		defer img.Close()
+		imgContent, err := ioutil.ReadAll(img)
+		if err != nil {
+			debug.PrintStack()
+			log.Error(err.Error())
+			return blog, err
+		}
+
+		images, err = ProcessBlogImage(id, imageHeader.Filename, imgContent)
+		if err != nil {
+			return blog, err
+		}
 	}
 
 	blog.Id = id
 	blog.Title = title
+	blog.Excerpt = excerpt
+	blog.Tags = tags
 	blog.Source = template.HTML(source)
-	blog.Content = template.HTML(content)
+	blog.Render()
 	blog.Date = date
 	blog.Author = author
 	blog.EditedBy = editor
@@ -230,19 +395,22 @@ func CreateBlog(img multipart.File, imageHeader *multipart.FileHeader, w http.Re
 	blog.DateEdited = editdate
 	blog.Images = images
 	blog.Slug = Slugify(blog.Date.Format("Jan-02-2006-3:04PM") + "-" + blog.Title)
-	rand.Seed(time.Now().UnixNano())
-	blog.Width = rand.Intn(9) + 1
+	blog.Width = ParseCardWidth(req.FormValue("card"))
+	blog.Featured = req.FormValue("featured") != ""
 	blog.Published = false
+	blog.State = StateDraft
+	blog.Unlisted = unlisted
 
 	localsession := session.Copy()
 	defer localsession.Close()
 
-	err = localsession.DB(database).C("blogs").Insert(blog)
+	err := localsession.DB(database).C("blogs").Insert(blog)
 	if err != nil {
 		debug.PrintStack()
 		log.Error(err.Error())
 		return blog, err
 	}
+	ScheduleRelatedRefresh()
 
 	return blog, nil
 }
@@ -7,14 +7,21 @@ import (
 	"fmt"
 	"github.com/gorilla/mux"
 	"gopkg.in/mgo.v2/bson"
+	"html/template"
+	"io/ioutil"
 	"net/http"
 	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
 )
 
 func BlogIndexHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
+	pinned := GetPinnedBlogs()
 	return T("pages/blog/index.html", pjax).Execute(w, map[string]interface{}{
-		"ctx":   ctx,
-		"blogs": GetBlogs(50, 0),
+		"ctx":    ctx,
+		"pinned": pinned,
+		"rows":   LayoutCards(GetBlogsExcept(50, blogIds(pinned))),
 	})
 }
 
@@ -23,10 +30,140 @@ func BlogWriteFormHandler(w http.ResponseWriter, req *http.Request, ctx *Context
 		return NotAuthedHandler(w, req, ctx, pjax)
 	}
 	return T("pages/blog/write.html", pjax).Execute(w, map[string]interface{}{
-		"ctx": ctx,
+		"ctx":       ctx,
+		"autosave":  autosaveFor(ctx.User.Id, nil),
+		"started":   time.Now().Unix(),
+		"cardSizes": CardSizes,
+	})
+}
+
+func BlogEditFormHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
+	id := mux.Vars(req)["id"]
+	if !bson.IsObjectIdHex(id) {
+		return BadRequestHandler(w, req, ctx, pjax)
+	}
+	post, err := GetBlogPostWithId(bson.ObjectIdHex(id))
+	if err != nil {
+		return NotFoundHandler(w, req, ctx, pjax)
+	}
+	if ctx.User == nil || !post.CanEdit(*ctx.User) {
+		return NotAuthedHandler(w, req, ctx, pjax)
+	}
+	return renderEditForm(w, ctx, pjax, post, post.EditBase())
+}
+
+// renderEditForm shows the write page for post, which may hold changes
+// that have not been saved yet. base is the text the editor started from,
+// for merging if someone else saves the post in the meantime.
+func renderEditForm(w http.ResponseWriter, ctx *Context, pjax bool, post BlogPost, base EditBase) error {
+	return T("pages/blog/write.html", pjax).Execute(w, map[string]interface{}{
+		"ctx":       ctx,
+		"post":      post,
+		"base":      base,
+		"tags":      strings.Join(post.Tags, ", "),
+		"autosave":  autosaveFor(ctx.User.Id, &post),
+		"started":   time.Now().Unix(),
+		"cardSizes": CardSizes,
 	})
 }
 
+// BlogEditHandler saves an edit if nobody else saved the post since the
+// editor loaded it. Otherwise it shows both versions so they can be merged.
+func BlogEditHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
+	id := req.FormValue("id")
+	if !bson.IsObjectIdHex(id) {
+		return BadRequestHandler(w, req, ctx, pjax)
+	}
+	saved, err := GetBlogPostWithId(bson.ObjectIdHex(id))
+	if err != nil {
+		return NotFoundHandler(w, req, ctx, pjax)
+	}
+	if ctx.User == nil || !saved.CanEdit(*ctx.User) {
+		return NotAuthedHandler(w, req, ctx, pjax)
+	}
+	version, err := strconv.Atoi(req.FormValue("version"))
+	if err != nil {
+		return BadRequestHandler(w, req, ctx, pjax)
+	}
+
+	post := saved
+	post.Version = version
+	post.Title = req.FormValue("title")
+	post.Source = template.HTML(req.FormValue("source"))
+	post.Excerpt = req.FormValue("excerpt")
+	post.Tags = ParseTags(req.FormValue("tags"))
+	post.Unlisted = req.FormValue("unlisted") != ""
+	post.Width = ParseCardWidth(req.FormValue("card"))
+	post.Featured = req.FormValue("featured") != ""
+	post.Edited = true
+	post.DateEdited = time.Now().UTC()
+	post.EditedBy = ctx.User.Id
+
+	newImage := false
+	if req.FormValue("remove-image") != "" {
+		post.Images = nil
+	}
+	if file, header, err := req.FormFile("blog-image"); err == nil {
+		defer file.Close()
+		content, err := ioutil.ReadAll(file)
+		if err != nil {
+			return err
+		}
+		images, err := ProcessBlogImage(post.Id, header.Filename, content)
+		if err != nil {
+			// Show the edit again rather than losing it.
+			ctx.Session.AddFlash(err.Error())
+			base, ok := ReadEditBase(req)
+			if !ok {
+				base = saved.EditBase()
+			}
+			return renderEditForm(w, ctx, pjax, post, base)
+		}
+		post.Images = images
+		newImage = true
+	}
+
+	post.ReviewEdit(*ctx.User)
+
+	err = post.Store()
+	if err == ErrConflict {
+		merged := EditBase{}
+		if base, ok := ReadEditBase(req); ok {
+			merged = base.Merge(saved, post)
+		} else {
+			// Forms from before the base was posted can only be merged
+			// by marking every difference.
+			merged = post.EditBase()
+			merged.Source = MarkDifferences(string(saved.Source), string(post.Source))
+		}
+		return T("pages/blog/conflict.html", pjax).Execute(w, map[string]interface{}{
+			"ctx":       ctx,
+			"post":      post,
+			"saved":     saved,
+			"tags":      strings.Join(post.Tags, ", "),
+			"diff":      DiffLines(string(saved.Source), string(post.Source)),
+			"merged":    merged,
+			"newImage":  newImage,
+			"cardSizes": CardSizes,
+		})
+	} else if err != nil {
+		return err
+	}
+
+	DiscardAutosave(ctx.User.Id, post.Id)
+	http.Redirect(w, req, post.IdUrl(), http.StatusSeeOther)
+	return nil
+}
+
+func BlogRenderHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
+	if ctx.User == nil || (!ctx.User.IsBlogAuthor && !ctx.User.IsAdmin) {
+		return NotAuthedHandler(w, req, ctx, pjax)
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, err = w.Write([]byte(RenderMarkdown(req.FormValue("source"))))
+	return err
+}
+
 func BlogReadHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
 	vars := mux.Vars(req)
 	slug := vars["slug"]
@@ -36,15 +173,22 @@ func BlogReadHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pja
 		return NotFoundHandler(w, req, ctx, pjax)
 	}
 
-	if !post.Published {
+	if !post.Published && (ctx.User == nil || !post.CanView(*ctx.User)) {
 		debug.PrintStack()
 		fmt.Println("blog: " + post.Title + " not published")
 		return NotFoundHandler(w, req, ctx, pjax)
 	}
 
 	return T("pages/blog/read.html", pjax).Execute(w, map[string]interface{}{
-		"ctx":  ctx,
-		"post": post,
+		"ctx":       ctx,
+		"post":      post,
+		"meta":      PostMeta(post, false),
+		"canEdit":   ctx.User != nil && post.CanEdit(*ctx.User),
+		"canReview": ctx.User != nil && post.CanView(*ctx.User),
+		"related":   post.GetRelatedPosts(),
+		"series":    post.GetSeriesNav(),
+		"popular":   GetPopularBlogs(PopularCount),
+		"trending":  GetTrendingBlogs(TrendingCount),
 	})
 }
 
@@ -59,7 +203,7 @@ func BlogStaticHandler(w http.ResponseWriter, req *http.Request, ctx *Context, p
 			return NotFoundHandler(w, req, ctx, pjax)
 		}
 
-		if !post.Published {
+		if !post.Published && (ctx.User == nil || !post.CanView(*ctx.User)) {
 			debug.PrintStack()
 			fmt.Println("blog: " + post.Title + " not published")
 			return NotFoundHandler(w, req, ctx, pjax)
@@ -78,7 +222,7 @@ func BlogWriteHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pj
 	}
 
 	file, header, err := req.FormFile("blog-image")
-	if err != nil {
+	if err != nil && err != http.ErrMissingFile {
 		ctx.Session.AddFlash(err.Error())
 		return BlogWriteFormHandler(w, req, ctx, pjax)
 	}
@@ -89,6 +233,252 @@ func BlogWriteHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pj
 		return BlogWriteFormHandler(w, req, ctx, pjax)
 	}
 
+	DiscardAutosave(ctx.User.Id, "")
 	http.Redirect(w, req, blog.IdUrl(), http.StatusFound)
 	return nil
 }
+
+func BlogShareHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
+	id := mux.Vars(req)["id"]
+	if !bson.IsObjectIdHex(id) {
+		return BadRequestHandler(w, req, ctx, pjax)
+	}
+	post, err := GetBlogPostWithId(bson.ObjectIdHex(id))
+	if err != nil {
+		return NotFoundHandler(w, req, ctx, pjax)
+	}
+	if ctx.User == nil || !post.CanEdit(*ctx.User) {
+		return NotAuthedHandler(w, req, ctx, pjax)
+	}
+
+	days, err := strconv.Atoi(req.FormValue("days"))
+	if err != nil || days < 1 {
+		days = PreviewDefaultDays
+	}
+	if days > PreviewMaxDays {
+		days = PreviewMaxDays
+	}
+	expires := time.Now().UTC().AddDate(0, 0, days)
+
+	return T("pages/blog/share.html", pjax).Execute(w, map[string]interface{}{
+		"ctx":     ctx,
+		"post":    post,
+		"url":     "https://" + config.Site.Domain + post.PreviewUrl(expires),
+		"expires": expires,
+	})
+}
+
+func BlogPreviewHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
+	id, err := ParsePreviewToken(mux.Vars(req)["token"])
+	if err == ErrPreviewExpired {
+		return httpError(http.StatusGone, w, req, ctx, pjax)
+	} else if err != nil {
+		return NotFoundHandler(w, req, ctx, pjax)
+	}
+	post, err := GetBlogPostWithId(id)
+	if err != nil {
+		return NotFoundHandler(w, req, ctx, pjax)
+	}
+
+	return T("pages/blog/read.html", pjax).Execute(w, map[string]interface{}{
+		"ctx":     ctx,
+		"post":    post,
+		"meta":    PostMeta(post, true),
+		"preview": true,
+	})
+}
+
+func BlogReviewQueueHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
+	if ctx.User == nil || (!ctx.User.IsBlogAuthor && !ctx.User.CanReview()) {
+		return NotAuthedHandler(w, req, ctx, pjax)
+	}
+	return T("pages/blog/reviews.html", pjax).Execute(w, map[string]interface{}{
+		"ctx":   ctx,
+		"blogs": GetReviewQueue(*ctx.User),
+	})
+}
+
+// reviewedPost loads the post a review request is about, if the user may
+// see it.
+func reviewedPost(req *http.Request, ctx *Context) (BlogPost, int) {
+	id := mux.Vars(req)["id"]
+	if !bson.IsObjectIdHex(id) {
+		return BlogPost{}, http.StatusBadRequest
+	}
+	post, err := GetBlogPostWithId(bson.ObjectIdHex(id))
+	if err != nil {
+		return post, http.StatusNotFound
+	}
+	if ctx.User == nil || !post.CanView(*ctx.User) {
+		return post, http.StatusUnauthorized
+	}
+	return post, http.StatusOK
+}
+
+func BlogReviewHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
+	post, code := reviewedPost(req, ctx)
+	if code != http.StatusOK {
+		return httpError(code, w, req, ctx, pjax)
+	}
+	return T("pages/blog/review.html", pjax).Execute(w, map[string]interface{}{
+		"ctx":        ctx,
+		"post":       post,
+		"paragraphs": post.ReviewParagraphs(),
+		"states":     post.NextStates(*ctx.User),
+		"canEdit":    post.CanEdit(*ctx.User),
+	})
+}
+
+func BlogTransitionHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
+	post, code := reviewedPost(req, ctx)
+	if code != http.StatusOK {
+		return httpError(code, w, req, ctx, pjax)
+	}
+	if err = post.Transition(req.FormValue("to"), *ctx.User, req.FormValue("note")); err != nil {
+		ctx.Session.AddFlash(err.Error())
+	} else {
+		ctx.Session.AddFlash(fmt.Sprintf("%q is now %s.", post.Title, strings.ToLower(StateName(post.State))))
+	}
+	http.Redirect(w, req, post.ReviewUrl(), http.StatusSeeOther)
+	return nil
+}
+
+func BlogCommentHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
+	post, code := reviewedPost(req, ctx)
+	if code != http.StatusOK {
+		return httpError(code, w, req, ctx, pjax)
+	}
+
+	if resolve := req.FormValue("resolve"); resolve != "" {
+		if !bson.IsObjectIdHex(resolve) || !post.CanEdit(*ctx.User) {
+			return BadRequestHandler(w, req, ctx, pjax)
+		}
+		err = post.ResolveReviewComment(bson.ObjectIdHex(resolve))
+	} else {
+		paragraph, perr := strconv.Atoi(req.FormValue("paragraph"))
+		if perr != nil {
+			return BadRequestHandler(w, req, ctx, pjax)
+		}
+		err = post.AddReviewComment(paragraph, req.FormValue("text"), *ctx.User)
+	}
+	if err != nil {
+		ctx.Session.AddFlash(err.Error())
+	}
+	http.Redirect(w, req, post.ReviewUrl()+"#paragraph-"+req.FormValue("paragraph"), http.StatusSeeOther)
+	return nil
+}
+
+func BlogPinHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
+	if ctx.User == nil || !ctx.User.IsAdmin {
+		return NotAuthedHandler(w, req, ctx, pjax)
+	}
+	id := mux.Vars(req)["id"]
+	if !bson.IsObjectIdHex(id) {
+		return BadRequestHandler(w, req, ctx, pjax)
+	}
+	post, err := GetBlogPostWithId(bson.ObjectIdHex(id))
+	if err != nil {
+		return NotFoundHandler(w, req, ctx, pjax)
+	}
+
+	if req.FormValue("unpin") != "" {
+		err = post.Unpin()
+	} else {
+		order, _ := strconv.Atoi(req.FormValue("order"))
+		expires := time.Time{}
+		if date := req.FormValue("expires"); date != "" {
+			if expires, err = time.Parse("2006-01-02", date); err != nil {
+				return BadRequestHandler(w, req, ctx, pjax)
+			}
+		}
+		err = post.Pin(order, expires)
+	}
+	if err != nil {
+		return err
+	}
+	http.Redirect(w, req, post.SlugUrl(), http.StatusSeeOther)
+	return nil
+}
+
+func BlogTrashHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
+	if ctx.User == nil || (!ctx.User.IsBlogAuthor && !ctx.User.IsAdmin) {
+		return NotAuthedHandler(w, req, ctx, pjax)
+	}
+	return T("pages/blog/trash.html", pjax).Execute(w, map[string]interface{}{
+		"ctx":   ctx,
+		"blogs": GetTrash(ctx.User),
+	})
+}
+
+func BlogDeleteHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
+	id := mux.Vars(req)["id"]
+	if !bson.IsObjectIdHex(id) {
+		return BadRequestHandler(w, req, ctx, pjax)
+	}
+	post, err := GetBlogPostWithId(bson.ObjectIdHex(id))
+	if err != nil {
+		return NotFoundHandler(w, req, ctx, pjax)
+	}
+	if ctx.User == nil || !post.CanEdit(*ctx.User) {
+		return NotAuthedHandler(w, req, ctx, pjax)
+	}
+
+	if err = post.Trash(*ctx.User); err != nil {
+		return err
+	}
+	ctx.Session.AddFlash(fmt.Sprintf("Moved %q to the trash.", post.Title))
+	http.Redirect(w, req, reverse("blog-trash"), http.StatusSeeOther)
+	return nil
+}
+
+func BlogRestoreHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
+	id := mux.Vars(req)["id"]
+	if !bson.IsObjectIdHex(id) {
+		return BadRequestHandler(w, req, ctx, pjax)
+	}
+	post, err := GetTrashedBlogPost(bson.ObjectIdHex(id))
+	if err != nil {
+		return NotFoundHandler(w, req, ctx, pjax)
+	}
+	if ctx.User == nil || !post.CanEdit(*ctx.User) {
+		return NotAuthedHandler(w, req, ctx, pjax)
+	}
+
+	if err = post.Restore(); err != nil {
+		return err
+	}
+	ctx.Session.AddFlash(fmt.Sprintf("Restored %q.", post.Title))
+	http.Redirect(w, req, reverse("blog-trash"), http.StatusSeeOther)
+	return nil
+}
+
+func BlogPurgeHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
+	id := mux.Vars(req)["id"]
+	if !bson.IsObjectIdHex(id) {
+		return BadRequestHandler(w, req, ctx, pjax)
+	}
+	post, err := GetTrashedBlogPost(bson.ObjectIdHex(id))
+	if err != nil {
+		return NotFoundHandler(w, req, ctx, pjax)
+	}
+	if ctx.User == nil || !post.CanEdit(*ctx.User) {
+		return NotAuthedHandler(w, req, ctx, pjax)
+	}
+
+	if err = post.Purge(); err != nil {
+		return err
+	}
+	ctx.Session.AddFlash(fmt.Sprintf("Permanently deleted %q.", post.Title))
+	http.Redirect(w, req, reverse("blog-trash"), http.StatusSeeOther)
+	return nil
+}
+
+func BlogExportHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
+	if ctx.User == nil || !ctx.User.IsAdmin {
+		return NotAuthedHandler(w, req, ctx, pjax)
+	}
+	filename := fmt.Sprintf("%s-%s.zip", config.Site.Domain, time.Now().UTC().Format("2006-01-02"))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	return WriteBlogExport(w, req.FormValue("drafts") != "")
+}
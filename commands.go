@@ -0,0 +1,46 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+// A Command is run from the command line, as `site <name> [args]`, instead
+// of starting the server. The database and router are set up beforehand.
+type Command struct {
+	Usage string
+	Run   func(args []string) error
+}
+
+var commands = map[string]Command{}
+
+// RegisterCommand makes a Command available under name.
+func RegisterCommand(name string, cmd Command) {
+	commands[name] = cmd
+}
+
+// RunCommand runs the named Command with the remaining arguments.
+func RunCommand(name string, args []string) error {
+	cmd, ok := commands[name]
+	if !ok {
+		printCommands()
+		return fmt.Errorf("unknown command %q", name)
+	}
+	return cmd.Run(args)
+}
+
+func printCommands() {
+	names := []string{}
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintf(os.Stderr, "Usage: %s [command] [args]\n\nCommands:\n", os.Args[0])
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %s %s\n", name, commands[name].Usage)
+	}
+}
@@ -37,6 +37,18 @@ type Configuration struct {
 		Title             string
 		Description       string
 		AllowRegistration bool
+		CodeTheme         string
+		Twemoji           bool
+		DefaultImage      string
+		Twitter           string
+	}
+	Blog struct {
+		TrashRetentionDays int
+	}
+	Git struct {
+		Dir           string
+		Author        string
+		WebhookSecret string
 	}
 }
 
@@ -0,0 +1,161 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"golang.org/x/net/html"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// TwemojiPath is where the twemoji SVGs are served from when Site.Twemoji
+// is enabled. They live in TwemojiFolder, named as in the twemoji
+// repository (e.g. 1f604.svg), and are fetched with the twemoji command.
+// Emoji without a file there are left as Unicode.
+const (
+	TwemojiPath    = "/assets/img/twemoji/"
+	TwemojiFolder  = "./static/img/twemoji/"
+	TwemojiVersion = "15.1.0"
+	twemojiSource  = "https://cdn.jsdelivr.net/gh/jdecked/twemoji@%s/assets/svg/%s.svg"
+)
+
+func init() {
+	RegisterCommand("twemoji", Command{
+		Usage: "[-version v]",
+		Run:   twemojiCommand,
+	})
+}
+
+var emojiShortcode = regexp.MustCompile(`:([a-z0-9_+-]+):`)
+
+// ReplaceEmoji replaces :shortcode: emoji in the text of an HTML fragment,
+// leaving code, pre and raw text elements alone.
+func ReplaceEmoji(fragment []byte) []byte {
+	var out bytes.Buffer
+	tokenizer := html.NewTokenizer(bytes.NewReader(fragment))
+	skip := 0
+	for {
+		tt := tokenizer.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		raw := tokenizer.Raw()
+		switch tt {
+		case html.StartTagToken, html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			switch string(name) {
+			case "code", "pre", "script", "style":
+				if tt == html.StartTagToken {
+					skip++
+				} else if skip > 0 {
+					skip--
+				}
+			}
+		case html.TextToken:
+			if skip == 0 {
+				raw = emojiShortcode.ReplaceAllFunc(raw, emojiFor)
+			}
+		}
+		out.Write(raw)
+	}
+	return out.Bytes()
+}
+
+func emojiFor(shortcode []byte) []byte {
+	name := string(shortcode[1 : len(shortcode)-1])
+	emoji, ok := emojiCodes[name]
+	if !ok {
+		return shortcode
+	}
+	if !config.Site.Twemoji {
+		return []byte(emoji)
+	}
+	file := twemojiName(emoji) + ".svg"
+	if _, err := os.Stat(TwemojiFolder + file); err != nil {
+		return []byte(emoji)
+	}
+	return []byte(fmt.Sprintf(`<img class="emoji" draggable="false" alt="%s" title=":%s:" src="%s%s">`,
+		emoji, name, TwemojiPath, file))
+}
+
+// twemojiName returns the twemoji file name for an emoji: its code points
+// in hex, without variation selectors unless it is a ZWJ sequence.
+func twemojiName(emoji string) string {
+	zwj := strings.ContainsRune(emoji, '\u200d')
+	points := []string{}
+	for _, r := range emoji {
+		if r == '\ufe0f' && !zwj {
+			continue
+		}
+		points = append(points, fmt.Sprintf("%x", r))
+	}
+	return strings.Join(points, "-")
+}
+
+// FetchTwemoji downloads the twemoji SVG of every emoji shortcode that
+// isn't in TwemojiFolder yet, and returns how many it fetched and how many
+// twemoji doesn't have.
+func FetchTwemoji(version string) (fetched int, missing int, err error) {
+	if err = os.MkdirAll(TwemojiFolder, 0775); err != nil {
+		return
+	}
+	names := map[string]bool{}
+	for _, emoji := range emojiCodes {
+		names[twemojiName(emoji)] = true
+	}
+	sorted := []string{}
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	for _, name := range sorted {
+		file := TwemojiFolder + name + ".svg"
+		if _, err := os.Stat(file); err == nil {
+			continue
+		}
+		resp, err := http.Get(fmt.Sprintf(twemojiSource, version, name))
+		if err != nil {
+			return fetched, missing, err
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return fetched, missing, err
+		}
+		if resp.StatusCode == http.StatusNotFound {
+			missing++
+			continue
+		} else if resp.StatusCode != http.StatusOK {
+			return fetched, missing, fmt.Errorf("twemoji: %s returned %s", name, resp.Status)
+		}
+		if err = ioutil.WriteFile(file, body, 0664); err != nil {
+			return fetched, missing, err
+		}
+		fetched++
+	}
+	return fetched, missing, nil
+}
+
+func twemojiCommand(args []string) error {
+	flags := flag.NewFlagSet("twemoji", flag.ContinueOnError)
+	version := flags.String("version", TwemojiVersion, "twemoji release to fetch")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return errors.New("usage: twemoji " + commands["twemoji"].Usage)
+	}
+	fetched, missing, err := FetchTwemoji(*version)
+	fmt.Printf("twemoji: %d fetched, %d not in twemoji %s\n", fetched, missing, *version)
+	return err
+}
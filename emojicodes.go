@@ -0,0 +1,877 @@
+// Code generated from markdown-it-emoji 1.1.0 (MIT). DO NOT EDIT.
+
+package main
+
+// emojiCodes maps emoji shortcodes, without colons, to their Unicode form.
+var emojiCodes = map[string]string{
+	"+1":                              "\U0001f44d",
+	"-1":                              "\U0001f44e",
+	"100":                             "\U0001f4af",
+	"1234":                            "\U0001f522",
+	"8ball":                           "\U0001f3b1",
+	"a":                               "\U0001f170\ufe0f",
+	"ab":                              "\U0001f18e",
+	"abc":                             "\U0001f524",
+	"abcd":                            "\U0001f521",
+	"accept":                          "\U0001f251",
+	"aerial_tramway":                  "\U0001f6a1",
+	"airplane":                        "\u2708\ufe0f",
+	"alarm_clock":                     "\u23f0",
+	"alien":                           "\U0001f47d",
+	"ambulance":                       "\U0001f691",
+	"anchor":                          "\u2693",
+	"angel":                           "\U0001f47c",
+	"anger":                           "\U0001f4a2",
+	"angry":                           "\U0001f620",
+	"anguished":                       "\U0001f627",
+	"ant":                             "\U0001f41c",
+	"apple":                           "\U0001f34e",
+	"aquarius":                        "\u2652",
+	"aries":                           "\u2648",
+	"arrow_backward":                  "\u25c0\ufe0f",
+	"arrow_double_down":               "\u23ec",
+	"arrow_double_up":                 "\u23eb",
+	"arrow_down":                      "\u2b07\ufe0f",
+	"arrow_down_small":                "\U0001f53d",
+	"arrow_forward":                   "\u25b6\ufe0f",
+	"arrow_heading_down":              "\u2935\ufe0f",
+	"arrow_heading_up":                "\u2934\ufe0f",
+	"arrow_left":                      "\u2b05\ufe0f",
+	"arrow_lower_left":                "\u2199\ufe0f",
+	"arrow_lower_right":               "\u2198\ufe0f",
+	"arrow_right":                     "\u27a1\ufe0f",
+	"arrow_right_hook":                "\u21aa\ufe0f",
+	"arrow_up":                        "\u2b06\ufe0f",
+	"arrow_up_down":                   "\u2195\ufe0f",
+	"arrow_up_small":                  "\U0001f53c",
+	"arrow_upper_left":                "\u2196\ufe0f",
+	"arrow_upper_right":               "\u2197\ufe0f",
+	"arrows_clockwise":                "\U0001f503",
+	"arrows_counterclockwise":         "\U0001f504",
+	"art":                             "\U0001f3a8",
+	"articulated_lorry":               "\U0001f69b",
+	"astonished":                      "\U0001f632",
+	"athletic_shoe":                   "\U0001f45f",
+	"atm":                             "\U0001f3e7",
+	"b":                               "\U0001f171\ufe0f",
+	"baby":                            "\U0001f476",
+	"baby_bottle":                     "\U0001f37c",
+	"baby_chick":                      "\U0001f424",
+	"baby_symbol":                     "\U0001f6bc",
+	"back":                            "\U0001f519",
+	"baggage_claim":                   "\U0001f6c4",
+	"balloon":                         "\U0001f388",
+	"ballot_box_with_check":           "\u2611\ufe0f",
+	"bamboo":                          "\U0001f38d",
+	"banana":                          "\U0001f34c",
+	"bangbang":                        "\u203c\ufe0f",
+	"bank":                            "\U0001f3e6",
+	"bar_chart":                       "\U0001f4ca",
+	"barber":                          "\U0001f488",
+	"baseball":                        "\u26be\ufe0f",
+	"basketball":                      "\U0001f3c0",
+	"bath":                            "\U0001f6c0",
+	"bathtub":                         "\U0001f6c1",
+	"battery":                         "\U0001f50b",
+	"bear":                            "\U0001f43b",
+	"bee":                             "\U0001f41d",
+	"beer":                            "\U0001f37a",
+	"beers":                           "\U0001f37b",
+	"beetle":                          "\U0001f41e",
+	"beginner":                        "\U0001f530",
+	"bell":                            "\U0001f514",
+	"bento":                           "\U0001f371",
+	"bicyclist":                       "\U0001f6b4",
+	"bike":                            "\U0001f6b2",
+	"bikini":                          "\U0001f459",
+	"bird":                            "\U0001f426",
+	"birthday":                        "\U0001f382",
+	"black_circle":                    "\u26ab",
+	"black_joker":                     "\U0001f0cf",
+	"black_large_square":              "\u2b1b",
+	"black_medium_small_square":       "\u25fe",
+	"black_medium_square":             "\u25fc\ufe0f",
+	"black_nib":                       "\u2712\ufe0f",
+	"black_small_square":              "\u25aa\ufe0f",
+	"black_square_button":             "\U0001f532",
+	"blossom":                         "\U0001f33c",
+	"blowfish":                        "\U0001f421",
+	"blue_book":                       "\U0001f4d8",
+	"blue_car":                        "\U0001f699",
+	"blue_heart":                      "\U0001f499",
+	"blush":                           "\U0001f60a",
+	"boar":                            "\U0001f417",
+	"boat":                            "\u26f5",
+	"bomb":                            "\U0001f4a3",
+	"book":                            "\U0001f4d6",
+	"bookmark":                        "\U0001f516",
+	"bookmark_tabs":                   "\U0001f4d1",
+	"books":                           "\U0001f4da",
+	"boom":                            "\U0001f4a5",
+	"boot":                            "\U0001f462",
+	"bouquet":                         "\U0001f490",
+	"bow":                             "\U0001f647",
+	"bowling":                         "\U0001f3b3",
+	"boy":                             "\U0001f466",
+	"bread":                           "\U0001f35e",
+	"bride_with_veil":                 "\U0001f470",
+	"bridge_at_night":                 "\U0001f309",
+	"briefcase":                       "\U0001f4bc",
+	"broken_heart":                    "\U0001f494",
+	"bug":                             "\U0001f41b",
+	"bulb":                            "\U0001f4a1",
+	"bullettrain_front":               "\U0001f685",
+	"bullettrain_side":                "\U0001f684",
+	"bus":                             "\U0001f68c",
+	"busstop":                         "\U0001f68f",
+	"bust_in_silhouette":              "\U0001f464",
+	"busts_in_silhouette":             "\U0001f465",
+	"cactus":                          "\U0001f335",
+	"cake":                            "\U0001f370",
+	"calendar":                        "\U0001f4c6",
+	"calling":                         "\U0001f4f2",
+	"camel":                           "\U0001f42b",
+	"camera":                          "\U0001f4f7",
+	"cancer":                          "\u264b",
+	"candy":                           "\U0001f36c",
+	"capital_abcd":                    "\U0001f520",
+	"capricorn":                       "\u2651",
+	"car":                             "\U0001f697",
+	"card_index":                      "\U0001f4c7",
+	"carousel_horse":                  "\U0001f3a0",
+	"cat":                             "\U0001f431",
+	"cat2":                            "\U0001f408",
+	"cd":                              "\U0001f4bf",
+	"chart":                           "\U0001f4b9",
+	"chart_with_downwards_trend":      "\U0001f4c9",
+	"chart_with_upwards_trend":        "\U0001f4c8",
+	"checkered_flag":                  "\U0001f3c1",
+	"cherries":                        "\U0001f352",
+	"cherry_blossom":                  "\U0001f338",
+	"chestnut":                        "\U0001f330",
+	"chicken":                         "\U0001f414",
+	"children_crossing":               "\U0001f6b8",
+	"chocolate_bar":                   "\U0001f36b",
+	"christmas_tree":                  "\U0001f384",
+	"church":                          "\u26ea",
+	"cinema":                          "\U0001f3a6",
+	"circus_tent":                     "\U0001f3aa",
+	"city_sunrise":                    "\U0001f307",
+	"city_sunset":                     "\U0001f306",
+	"cl":                              "\U0001f191",
+	"clap":                            "\U0001f44f",
+	"clapper":                         "\U0001f3ac",
+	"clipboard":                       "\U0001f4cb",
+	"clock1":                          "\U0001f550",
+	"clock10":                         "\U0001f559",
+	"clock1030":                       "\U0001f565",
+	"clock11":                         "\U0001f55a",
+	"clock1130":                       "\U0001f566",
+	"clock12":                         "\U0001f55b",
+	"clock1230":                       "\U0001f567",
+	"clock130":                        "\U0001f55c",
+	"clock2":                          "\U0001f551",
+	"clock230":                        "\U0001f55d",
+	"clock3":                          "\U0001f552",
+	"clock330":                        "\U0001f55e",
+	"clock4":                          "\U0001f553",
+	"clock430":                        "\U0001f55f",
+	"clock5":                          "\U0001f554",
+	"clock530":                        "\U0001f560",
+	"clock6":                          "\U0001f555",
+	"clock630":                        "\U0001f561",
+	"clock7":                          "\U0001f556",
+	"clock730":                        "\U0001f562",
+	"clock8":                          "\U0001f557",
+	"clock830":                        "\U0001f563",
+	"clock9":                          "\U0001f558",
+	"clock930":                        "\U0001f564",
+	"closed_book":                     "\U0001f4d5",
+	"closed_lock_with_key":            "\U0001f510",
+	"closed_umbrella":                 "\U0001f302",
+	"cloud":                           "\u2601\ufe0f",
+	"clubs":                           "\u2663\ufe0f",
+	"cn":                              "\U0001f1e8\U0001f1f3",
+	"cocktail":                        "\U0001f378",
+	"coffee":                          "\u2615",
+	"cold_sweat":                      "\U0001f630",
+	"collision":                       "\U0001f4a5",
+	"computer":                        "\U0001f4bb",
+	"confetti_ball":                   "\U0001f38a",
+	"confounded":                      "\U0001f616",
+	"confused":                        "\U0001f615",
+	"congratulations":                 "\u3297\ufe0f",
+	"construction":                    "\U0001f6a7",
+	"construction_worker":             "\U0001f477",
+	"convenience_store":               "\U0001f3ea",
+	"cookie":                          "\U0001f36a",
+	"cool":                            "\U0001f192",
+	"cop":                             "\U0001f46e",
+	"copyright":                       "\u00a9\ufe0f",
+	"corn":                            "\U0001f33d",
+	"couple":                          "\U0001f46b",
+	"couple_with_heart":               "\U0001f491",
+	"couplekiss":                      "\U0001f48f",
+	"cow":                             "\U0001f42e",
+	"cow2":                            "\U0001f404",
+	"credit_card":                     "\U0001f4b3",
+	"crescent_moon":                   "\U0001f319",
+	"crocodile":                       "\U0001f40a",
+	"crossed_flags":                   "\U0001f38c",
+	"crown":                           "\U0001f451",
+	"cry":                             "\U0001f622",
+	"crying_cat_face":                 "\U0001f63f",
+	"crystal_ball":                    "\U0001f52e",
+	"cupid":                           "\U0001f498",
+	"curly_loop":                      "\u27b0",
+	"currency_exchange":               "\U0001f4b1",
+	"curry":                           "\U0001f35b",
+	"custard":                         "\U0001f36e",
+	"customs":                         "\U0001f6c3",
+	"cyclone":                         "\U0001f300",
+	"dancer":                          "\U0001f483",
+	"dancers":                         "\U0001f46f",
+	"dango":                           "\U0001f361",
+	"dart":                            "\U0001f3af",
+	"dash":                            "\U0001f4a8",
+	"date":                            "\U0001f4c5",
+	"de":                              "\U0001f1e9\U0001f1ea",
+	"deciduous_tree":                  "\U0001f333",
+	"department_store":                "\U0001f3ec",
+	"diamond_shape_with_a_dot_inside": "\U0001f4a0",
+	"diamonds":                        "\u2666\ufe0f",
+	"disappointed":                    "\U0001f61e",
+	"disappointed_relieved":           "\U0001f625",
+	"dizzy":                           "\U0001f4ab",
+	"dizzy_face":                      "\U0001f635",
+	"do_not_litter":                   "\U0001f6af",
+	"dog":                             "\U0001f436",
+	"dog2":                            "\U0001f415",
+	"dollar":                          "\U0001f4b5",
+	"dolls":                           "\U0001f38e",
+	"dolphin":                         "\U0001f42c",
+	"door":                            "\U0001f6aa",
+	"doughnut":                        "\U0001f369",
+	"dragon":                          "\U0001f409",
+	"dragon_face":                     "\U0001f432",
+	"dress":                           "\U0001f457",
+	"dromedary_camel":                 "\U0001f42a",
+	"droplet":                         "\U0001f4a7",
+	"dvd":                             "\U0001f4c0",
+	"e-mail":                          "\U0001f4e7",
+	"ear":                             "\U0001f442",
+	"ear_of_rice":                     "\U0001f33e",
+	"earth_africa":                    "\U0001f30d",
+	"earth_americas":                  "\U0001f30e",
+	"earth_asia":                      "\U0001f30f",
+	"egg":                             "\U0001f373",
+	"eggplant":                        "\U0001f346",
+	"eight":                           "\u0038\ufe0f\u20e3",
+	"eight_pointed_black_star":        "\u2734\ufe0f",
+	"eight_spoked_asterisk":           "\u2733\ufe0f",
+	"electric_plug":                   "\U0001f50c",
+	"elephant":                        "\U0001f418",
+	"email":                           "\u2709\ufe0f",
+	"end":                             "\U0001f51a",
+	"envelope":                        "\u2709\ufe0f",
+	"envelope_with_arrow":             "\U0001f4e9",
+	"es":                              "\U0001f1ea\U0001f1f8",
+	"euro":                            "\U0001f4b6",
+	"european_castle":                 "\U0001f3f0",
+	"european_post_office":            "\U0001f3e4",
+	"evergreen_tree":                  "\U0001f332",
+	"exclamation":                     "\u2757",
+	"expressionless":                  "\U0001f611",
+	"eyeglasses":                      "\U0001f453",
+	"eyes":                            "\U0001f440",
+	"facepunch":                       "\U0001f44a",
+	"factory":                         "\U0001f3ed",
+	"fallen_leaf":                     "\U0001f342",
+	"family":                          "\U0001f46a",
+	"fast_forward":                    "\u23e9",
+	"fax":                             "\U0001f4e0",
+	"fearful":                         "\U0001f628",
+	"feet":                            "\U0001f43e",
+	"ferris_wheel":                    "\U0001f3a1",
+	"file_folder":                     "\U0001f4c1",
+	"fire":                            "\U0001f525",
+	"fire_engine":                     "\U0001f692",
+	"fireworks":                       "\U0001f386",
+	"first_quarter_moon":              "\U0001f313",
+	"first_quarter_moon_with_face":    "\U0001f31b",
+	"fish":                            "\U0001f41f",
+	"fish_cake":                       "\U0001f365",
+	"fishing_pole_and_fish":           "\U0001f3a3",
+	"fist":                            "\u270a",
+	"five":                            "\u0035\ufe0f\u20e3",
+	"flags":                           "\U0001f38f",
+	"flashlight":                      "\U0001f526",
+	"flipper":                         "\U0001f42c",
+	"floppy_disk":                     "\U0001f4be",
+	"flower_playing_cards":            "\U0001f3b4",
+	"flushed":                         "\U0001f633",
+	"foggy":                           "\U0001f301",
+	"football":                        "\U0001f3c8",
+	"footprints":                      "\U0001f463",
+	"fork_and_knife":                  "\U0001f374",
+	"fountain":                        "\u26f2",
+	"four":                            "\u0034\ufe0f\u20e3",
+	"four_leaf_clover":                "\U0001f340",
+	"fr":                              "\U0001f1eb\U0001f1f7",
+	"free":                            "\U0001f193",
+	"fried_shrimp":                    "\U0001f364",
+	"fries":                           "\U0001f35f",
+	"frog":                            "\U0001f438",
+	"frowning":                        "\U0001f626",
+	"fuelpump":                        "\u26fd",
+	"full_moon":                       "\U0001f315",
+	"full_moon_with_face":             "\U0001f31d",
+	"game_die":                        "\U0001f3b2",
+	"gb":                              "\U0001f1ec\U0001f1e7",
+	"gem":                             "\U0001f48e",
+	"gemini":                          "\u264a",
+	"ghost":                           "\U0001f47b",
+	"gift":                            "\U0001f381",
+	"gift_heart":                      "\U0001f49d",
+	"girl":                            "\U0001f467",
+	"globe_with_meridians":            "\U0001f310",
+	"goat":                            "\U0001f410",
+	"golf":                            "\u26f3",
+	"grapes":                          "\U0001f347",
+	"green_apple":                     "\U0001f34f",
+	"green_book":                      "\U0001f4d7",
+	"green_heart":                     "\U0001f49a",
+	"grey_exclamation":                "\u2755",
+	"grey_question":                   "\u2754",
+	"grimacing":                       "\U0001f62c",
+	"grin":                            "\U0001f601",
+	"grinning":                        "\U0001f600",
+	"guardsman":                       "\U0001f482",
+	"guitar":                          "\U0001f3b8",
+	"gun":                             "\U0001f52b",
+	"haircut":                         "\U0001f487",
+	"hamburger":                       "\U0001f354",
+	"hammer":                          "\U0001f528",
+	"hamster":                         "\U0001f439",
+	"hand":                            "\u270b",
+	"handbag":                         "\U0001f45c",
+	"hankey":                          "\U0001f4a9",
+	"hash":                            "\u0023\ufe0f\u20e3",
+	"hatched_chick":                   "\U0001f425",
+	"hatching_chick":                  "\U0001f423",
+	"headphones":                      "\U0001f3a7",
+	"hear_no_evil":                    "\U0001f649",
+	"heart":                           "\u2764\ufe0f",
+	"heart_decoration":                "\U0001f49f",
+	"heart_eyes":                      "\U0001f60d",
+	"heart_eyes_cat":                  "\U0001f63b",
+	"heartbeat":                       "\U0001f493",
+	"heartpulse":                      "\U0001f497",
+	"hearts":                          "\u2665\ufe0f",
+	"heavy_check_mark":                "\u2714\ufe0f",
+	"heavy_division_sign":             "\u2797",
+	"heavy_dollar_sign":               "\U0001f4b2",
+	"heavy_exclamation_mark":          "\u2757",
+	"heavy_minus_sign":                "\u2796",
+	"heavy_multiplication_x":          "\u2716\ufe0f",
+	"heavy_plus_sign":                 "\u2795",
+	"helicopter":                      "\U0001f681",
+	"herb":                            "\U0001f33f",
+	"hibiscus":                        "\U0001f33a",
+	"high_brightness":                 "\U0001f506",
+	"high_heel":                       "\U0001f460",
+	"hocho":                           "\U0001f52a",
+	"honey_pot":                       "\U0001f36f",
+	"honeybee":                        "\U0001f41d",
+	"horse":                           "\U0001f434",
+	"horse_racing":                    "\U0001f3c7",
+	"hospital":                        "\U0001f3e5",
+	"hotel":                           "\U0001f3e8",
+	"hotsprings":                      "\u2668\ufe0f",
+	"hourglass":                       "\u231b",
+	"hourglass_flowing_sand":          "\u23f3",
+	"house":                           "\U0001f3e0",
+	"house_with_garden":               "\U0001f3e1",
+	"hushed":                          "\U0001f62f",
+	"ice_cream":                       "\U0001f368",
+	"icecream":                        "\U0001f366",
+	"id":                              "\U0001f194",
+	"ideograph_advantage":             "\U0001f250",
+	"imp":                             "\U0001f47f",
+	"inbox_tray":                      "\U0001f4e5",
+	"incoming_envelope":               "\U0001f4e8",
+	"information_desk_person":         "\U0001f481",
+	"information_source":              "\u2139\ufe0f",
+	"innocent":                        "\U0001f607",
+	"interrobang":                     "\u2049\ufe0f",
+	"iphone":                          "\U0001f4f1",
+	"it":                              "\U0001f1ee\U0001f1f9",
+	"izakaya_lantern":                 "\U0001f3ee",
+	"jack_o_lantern":                  "\U0001f383",
+	"japan":                           "\U0001f5fe",
+	"japanese_castle":                 "\U0001f3ef",
+	"japanese_goblin":                 "\U0001f47a",
+	"japanese_ogre":                   "\U0001f479",
+	"jeans":                           "\U0001f456",
+	"joy":                             "\U0001f602",
+	"joy_cat":                         "\U0001f639",
+	"jp":                              "\U0001f1ef\U0001f1f5",
+	"key":                             "\U0001f511",
+	"keycap_ten":                      "\U0001f51f",
+	"kimono":                          "\U0001f458",
+	"kiss":                            "\U0001f48b",
+	"kissing":                         "\U0001f617",
+	"kissing_cat":                     "\U0001f63d",
+	"kissing_closed_eyes":             "\U0001f61a",
+	"kissing_heart":                   "\U0001f618",
+	"kissing_smiling_eyes":            "\U0001f619",
+	"knife":                           "\U0001f52a",
+	"koala":                           "\U0001f428",
+	"koko":                            "\U0001f201",
+	"kr":                              "\U0001f1f0\U0001f1f7",
+	"lantern":                         "\U0001f3ee",
+	"large_blue_circle":               "\U0001f535",
+	"large_blue_diamond":              "\U0001f537",
+	"large_orange_diamond":            "\U0001f536",
+	"last_quarter_moon":               "\U0001f317",
+	"last_quarter_moon_with_face":     "\U0001f31c",
+	"laughing":                        "\U0001f606",
+	"leaves":                          "\U0001f343",
+	"ledger":                          "\U0001f4d2",
+	"left_luggage":                    "\U0001f6c5",
+	"left_right_arrow":                "\u2194\ufe0f",
+	"leftwards_arrow_with_hook":       "\u21a9\ufe0f",
+	"lemon":                           "\U0001f34b",
+	"leo":                             "\u264c",
+	"leopard":                         "\U0001f406",
+	"libra":                           "\u264e",
+	"light_rail":                      "\U0001f688",
+	"link":                            "\U0001f517",
+	"lips":                            "\U0001f444",
+	"lipstick":                        "\U0001f484",
+	"lock":                            "\U0001f512",
+	"lock_with_ink_pen":               "\U0001f50f",
+	"lollipop":                        "\U0001f36d",
+	"loop":                            "\u27bf",
+	"loud_sound":                      "\U0001f50a",
+	"loudspeaker":                     "\U0001f4e2",
+	"love_hotel":                      "\U0001f3e9",
+	"love_letter":                     "\U0001f48c",
+	"low_brightness":                  "\U0001f505",
+	"m":                               "\u24c2\ufe0f",
+	"mag":                             "\U0001f50d",
+	"mag_right":                       "\U0001f50e",
+	"mahjong":                         "\U0001f004",
+	"mailbox":                         "\U0001f4eb",
+	"mailbox_closed":                  "\U0001f4ea",
+	"mailbox_with_mail":               "\U0001f4ec",
+	"mailbox_with_no_mail":            "\U0001f4ed",
+	"man":                             "\U0001f468",
+	"man_with_gua_pi_mao":             "\U0001f472",
+	"man_with_turban":                 "\U0001f473",
+	"mans_shoe":                       "\U0001f45e",
+	"maple_leaf":                      "\U0001f341",
+	"mask":                            "\U0001f637",
+	"massage":                         "\U0001f486",
+	"meat_on_bone":                    "\U0001f356",
+	"mega":                            "\U0001f4e3",
+	"melon":                           "\U0001f348",
+	"memo":                            "\U0001f4dd",
+	"mens":                            "\U0001f6b9",
+	"metro":                           "\U0001f687",
+	"microphone":                      "\U0001f3a4",
+	"microscope":                      "\U0001f52c",
+	"milky_way":                       "\U0001f30c",
+	"minibus":                         "\U0001f690",
+	"minidisc":                        "\U0001f4bd",
+	"mobile_phone_off":                "\U0001f4f4",
+	"money_with_wings":                "\U0001f4b8",
+	"moneybag":                        "\U0001f4b0",
+	"monkey":                          "\U0001f412",
+	"monkey_face":                     "\U0001f435",
+	"monorail":                        "\U0001f69d",
+	"moon":                            "\U0001f314",
+	"mortar_board":                    "\U0001f393",
+	"mount_fuji":                      "\U0001f5fb",
+	"mountain_bicyclist":              "\U0001f6b5",
+	"mountain_cableway":               "\U0001f6a0",
+	"mountain_railway":                "\U0001f69e",
+	"mouse":                           "\U0001f42d",
+	"mouse2":                          "\U0001f401",
+	"movie_camera":                    "\U0001f3a5",
+	"moyai":                           "\U0001f5ff",
+	"muscle":                          "\U0001f4aa",
+	"mushroom":                        "\U0001f344",
+	"musical_keyboard":                "\U0001f3b9",
+	"musical_note":                    "\U0001f3b5",
+	"musical_score":                   "\U0001f3bc",
+	"mute":                            "\U0001f507",
+	"nail_care":                       "\U0001f485",
+	"name_badge":                      "\U0001f4db",
+	"necktie":                         "\U0001f454",
+	"negative_squared_cross_mark":     "\u274e",
+	"neutral_face":                    "\U0001f610",
+	"new":                             "\U0001f195",
+	"new_moon":                        "\U0001f311",
+	"new_moon_with_face":              "\U0001f31a",
+	"newspaper":                       "\U0001f4f0",
+	"ng":                              "\U0001f196",
+	"night_with_stars":                "\U0001f303",
+	"nine":                            "\u0039\ufe0f\u20e3",
+	"no_bell":                         "\U0001f515",
+	"no_bicycles":                     "\U0001f6b3",
+	"no_entry":                        "\u26d4",
+	"no_entry_sign":                   "\U0001f6ab",
+	"no_good":                         "\U0001f645",
+	"no_mobile_phones":                "\U0001f4f5",
+	"no_mouth":                        "\U0001f636",
+	"no_pedestrians":                  "\U0001f6b7",
+	"no_smoking":                      "\U0001f6ad",
+	"non-potable_water":               "\U0001f6b1",
+	"nose":                            "\U0001f443",
+	"notebook":                        "\U0001f4d3",
+	"notebook_with_decorative_cover":  "\U0001f4d4",
+	"notes":                           "\U0001f3b6",
+	"nut_and_bolt":                    "\U0001f529",
+	"o":                               "\u2b55",
+	"o2":                              "\U0001f17e\ufe0f",
+	"ocean":                           "\U0001f30a",
+	"octopus":                         "\U0001f419",
+	"oden":                            "\U0001f362",
+	"office":                          "\U0001f3e2",
+	"ok":                              "\U0001f197",
+	"ok_hand":                         "\U0001f44c",
+	"ok_woman":                        "\U0001f646",
+	"older_man":                       "\U0001f474",
+	"older_woman":                     "\U0001f475",
+	"on":                              "\U0001f51b",
+	"oncoming_automobile":             "\U0001f698",
+	"oncoming_bus":                    "\U0001f68d",
+	"oncoming_police_car":             "\U0001f694",
+	"oncoming_taxi":                   "\U0001f696",
+	"one":                             "\u0031\ufe0f\u20e3",
+	"open_book":                       "\U0001f4d6",
+	"open_file_folder":                "\U0001f4c2",
+	"open_hands":                      "\U0001f450",
+	"open_mouth":                      "\U0001f62e",
+	"ophiuchus":                       "\u26ce",
+	"orange_book":                     "\U0001f4d9",
+	"outbox_tray":                     "\U0001f4e4",
+	"ox":                              "\U0001f402",
+	"package":                         "\U0001f4e6",
+	"page_facing_up":                  "\U0001f4c4",
+	"page_with_curl":                  "\U0001f4c3",
+	"pager":                           "\U0001f4df",
+	"palm_tree":                       "\U0001f334",
+	"panda_face":                      "\U0001f43c",
+	"paperclip":                       "\U0001f4ce",
+	"parking":                         "\U0001f17f\ufe0f",
+	"part_alternation_mark":           "\u303d\ufe0f",
+	"partly_sunny":                    "\u26c5",
+	"passport_control":                "\U0001f6c2",
+	"paw_prints":                      "\U0001f43e",
+	"peach":                           "\U0001f351",
+	"pear":                            "\U0001f350",
+	"pencil":                          "\U0001f4dd",
+	"pencil2":                         "\u270f\ufe0f",
+	"penguin":                         "\U0001f427",
+	"pensive":                         "\U0001f614",
+	"performing_arts":                 "\U0001f3ad",
+	"persevere":                       "\U0001f623",
+	"person_frowning":                 "\U0001f64d",
+	"person_with_blond_hair":          "\U0001f471",
+	"person_with_pouting_face":        "\U0001f64e",
+	"phone":                           "\u260e\ufe0f",
+	"pig":                             "\U0001f437",
+	"pig2":                            "\U0001f416",
+	"pig_nose":                        "\U0001f43d",
+	"pill":                            "\U0001f48a",
+	"pineapple":                       "\U0001f34d",
+	"pisces":                          "\u2653",
+	"pizza":                           "\U0001f355",
+	"point_down":                      "\U0001f447",
+	"point_left":                      "\U0001f448",
+	"point_right":                     "\U0001f449",
+	"point_up":                        "\u261d\ufe0f",
+	"point_up_2":                      "\U0001f446",
+	"police_car":                      "\U0001f693",
+	"poodle":                          "\U0001f429",
+	"poop":                            "\U0001f4a9",
+	"post_office":                     "\U0001f3e3",
+	"postal_horn":                     "\U0001f4ef",
+	"postbox":                         "\U0001f4ee",
+	"potable_water":                   "\U0001f6b0",
+	"pouch":                           "\U0001f45d",
+	"poultry_leg":                     "\U0001f357",
+	"pound":                           "\U0001f4b7",
+	"pouting_cat":                     "\U0001f63e",
+	"pray":                            "\U0001f64f",
+	"princess":                        "\U0001f478",
+	"punch":                           "\U0001f44a",
+	"purple_heart":                    "\U0001f49c",
+	"purse":                           "\U0001f45b",
+	"pushpin":                         "\U0001f4cc",
+	"put_litter_in_its_place":         "\U0001f6ae",
+	"question":                        "\u2753",
+	"rabbit":                          "\U0001f430",
+	"rabbit2":                         "\U0001f407",
+	"racehorse":                       "\U0001f40e",
+	"radio":                           "\U0001f4fb",
+	"radio_button":                    "\U0001f518",
+	"rage":                            "\U0001f621",
+	"railway_car":                     "\U0001f683",
+	"rainbow":                         "\U0001f308",
+	"raised_hand":                     "\u270b",
+	"raised_hands":                    "\U0001f64c",
+	"raising_hand":                    "\U0001f64b",
+	"ram":                             "\U0001f40f",
+	"ramen":                           "\U0001f35c",
+	"rat":                             "\U0001f400",
+	"recycle":                         "\u267b\ufe0f",
+	"red_car":                         "\U0001f697",
+	"red_circle":                      "\U0001f534",
+	"registered":                      "\u00ae\ufe0f",
+	"relaxed":                         "\u263a\ufe0f",
+	"relieved":                        "\U0001f60c",
+	"repeat":                          "\U0001f501",
+	"repeat_one":                      "\U0001f502",
+	"restroom":                        "\U0001f6bb",
+	"revolving_hearts":                "\U0001f49e",
+	"rewind":                          "\u23ea",
+	"ribbon":                          "\U0001f380",
+	"rice":                            "\U0001f35a",
+	"rice_ball":                       "\U0001f359",
+	"rice_cracker":                    "\U0001f358",
+	"rice_scene":                      "\U0001f391",
+	"ring":                            "\U0001f48d",
+	"rocket":                          "\U0001f680",
+	"roller_coaster":                  "\U0001f3a2",
+	"rooster":                         "\U0001f413",
+	"rose":                            "\U0001f339",
+	"rotating_light":                  "\U0001f6a8",
+	"round_pushpin":                   "\U0001f4cd",
+	"rowboat":                         "\U0001f6a3",
+	"ru":                              "\U0001f1f7\U0001f1fa",
+	"rugby_football":                  "\U0001f3c9",
+	"runner":                          "\U0001f3c3",
+	"running":                         "\U0001f3c3",
+	"running_shirt_with_sash":         "\U0001f3bd",
+	"sa":                              "\U0001f202\ufe0f",
+	"sagittarius":                     "\u2650",
+	"sailboat":                        "\u26f5",
+	"sake":                            "\U0001f376",
+	"sandal":                          "\U0001f461",
+	"santa":                           "\U0001f385",
+	"satellite":                       "\U0001f4e1",
+	"satisfied":                       "\U0001f606",
+	"saxophone":                       "\U0001f3b7",
+	"school":                          "\U0001f3eb",
+	"school_satchel":                  "\U0001f392",
+	"scissors":                        "\u2702\ufe0f",
+	"scorpius":                        "\u264f",
+	"scream":                          "\U0001f631",
+	"scream_cat":                      "\U0001f640",
+	"scroll":                          "\U0001f4dc",
+	"seat":                            "\U0001f4ba",
+	"secret":                          "\u3299\ufe0f",
+	"see_no_evil":                     "\U0001f648",
+	"seedling":                        "\U0001f331",
+	"seven":                           "\u0037\ufe0f\u20e3",
+	"shaved_ice":                      "\U0001f367",
+	"sheep":                           "\U0001f411",
+	"shell":                           "\U0001f41a",
+	"ship":                            "\U0001f6a2",
+	"shirt":                           "\U0001f455",
+	"shit":                            "\U0001f4a9",
+	"shoe":                            "\U0001f45e",
+	"shower":                          "\U0001f6bf",
+	"signal_strength":                 "\U0001f4f6",
+	"six":                             "\u0036\ufe0f\u20e3",
+	"six_pointed_star":                "\U0001f52f",
+	"ski":                             "\U0001f3bf",
+	"skull":                           "\U0001f480",
+	"sleeping":                        "\U0001f634",
+	"sleepy":                          "\U0001f62a",
+	"slot_machine":                    "\U0001f3b0",
+	"small_blue_diamond":              "\U0001f539",
+	"small_orange_diamond":            "\U0001f538",
+	"small_red_triangle":              "\U0001f53a",
+	"small_red_triangle_down":         "\U0001f53b",
+	"smile":                           "\U0001f604",
+	"smile_cat":                       "\U0001f638",
+	"smiley":                          "\U0001f603",
+	"smiley_cat":                      "\U0001f63a",
+	"smiling_imp":                     "\U0001f608",
+	"smirk":                           "\U0001f60f",
+	"smirk_cat":                       "\U0001f63c",
+	"smoking":                         "\U0001f6ac",
+	"snail":                           "\U0001f40c",
+	"snake":                           "\U0001f40d",
+	"snowboarder":                     "\U0001f3c2",
+	"snowflake":                       "\u2744\ufe0f",
+	"snowman":                         "\u26c4",
+	"sob":                             "\U0001f62d",
+	"soccer":                          "\u26bd",
+	"soon":                            "\U0001f51c",
+	"sos":                             "\U0001f198",
+	"sound":                           "\U0001f509",
+	"space_invader":                   "\U0001f47e",
+	"spades":                          "\u2660\ufe0f",
+	"spaghetti":                       "\U0001f35d",
+	"sparkle":                         "\u2747\ufe0f",
+	"sparkler":                        "\U0001f387",
+	"sparkles":                        "\u2728",
+	"sparkling_heart":                 "\U0001f496",
+	"speak_no_evil":                   "\U0001f64a",
+	"speaker":                         "\U0001f508",
+	"speech_balloon":                  "\U0001f4ac",
+	"speedboat":                       "\U0001f6a4",
+	"star":                            "\u2b50",
+	"star2":                           "\U0001f31f",
+	"stars":                           "\U0001f320",
+	"station":                         "\U0001f689",
+	"statue_of_liberty":               "\U0001f5fd",
+	"steam_locomotive":                "\U0001f682",
+	"stew":                            "\U0001f372",
+	"straight_ruler":                  "\U0001f4cf",
+	"strawberry":                      "\U0001f353",
+	"stuck_out_tongue":                "\U0001f61b",
+	"stuck_out_tongue_closed_eyes":    "\U0001f61d",
+	"stuck_out_tongue_winking_eye":    "\U0001f61c",
+	"sun_with_face":                   "\U0001f31e",
+	"sunflower":                       "\U0001f33b",
+	"sunglasses":                      "\U0001f60e",
+	"sunny":                           "\u2600\ufe0f",
+	"sunrise":                         "\U0001f305",
+	"sunrise_over_mountains":          "\U0001f304",
+	"surfer":                          "\U0001f3c4",
+	"sushi":                           "\U0001f363",
+	"suspension_railway":              "\U0001f69f",
+	"sweat":                           "\U0001f613",
+	"sweat_drops":                     "\U0001f4a6",
+	"sweat_smile":                     "\U0001f605",
+	"sweet_potato":                    "\U0001f360",
+	"swimmer":                         "\U0001f3ca",
+	"symbols":                         "\U0001f523",
+	"syringe":                         "\U0001f489",
+	"tada":                            "\U0001f389",
+	"tanabata_tree":                   "\U0001f38b",
+	"tangerine":                       "\U0001f34a",
+	"taurus":                          "\u2649",
+	"taxi":                            "\U0001f695",
+	"tea":                             "\U0001f375",
+	"telephone":                       "\u260e\ufe0f",
+	"telephone_receiver":              "\U0001f4de",
+	"telescope":                       "\U0001f52d",
+	"tennis":                          "\U0001f3be",
+	"tent":                            "\u26fa",
+	"thought_balloon":                 "\U0001f4ad",
+	"three":                           "\u0033\ufe0f\u20e3",
+	"thumbsdown":                      "\U0001f44e",
+	"thumbsup":                        "\U0001f44d",
+	"ticket":                          "\U0001f3ab",
+	"tiger":                           "\U0001f42f",
+	"tiger2":                          "\U0001f405",
+	"tired_face":                      "\U0001f62b",
+	"tm":                              "\u2122\ufe0f",
+	"toilet":                          "\U0001f6bd",
+	"tokyo_tower":                     "\U0001f5fc",
+	"tomato":                          "\U0001f345",
+	"tongue":                          "\U0001f445",
+	"top":                             "\U0001f51d",
+	"tophat":                          "\U0001f3a9",
+	"tractor":                         "\U0001f69c",
+	"traffic_light":                   "\U0001f6a5",
+	"train":                           "\U0001f68b",
+	"train2":                          "\U0001f686",
+	"tram":                            "\U0001f68a",
+	"triangular_flag_on_post":         "\U0001f6a9",
+	"triangular_ruler":                "\U0001f4d0",
+	"trident":                         "\U0001f531",
+	"triumph":                         "\U0001f624",
+	"trolleybus":                      "\U0001f68e",
+	"trophy":                          "\U0001f3c6",
+	"tropical_drink":                  "\U0001f379",
+	"tropical_fish":                   "\U0001f420",
+	"truck":                           "\U0001f69a",
+	"trumpet":                         "\U0001f3ba",
+	"tshirt":                          "\U0001f455",
+	"tulip":                           "\U0001f337",
+	"turtle":                          "\U0001f422",
+	"tv":                              "\U0001f4fa",
+	"twisted_rightwards_arrows":       "\U0001f500",
+	"two":                             "\u0032\ufe0f\u20e3",
+	"two_hearts":                      "\U0001f495",
+	"two_men_holding_hands":           "\U0001f46c",
+	"two_women_holding_hands":         "\U0001f46d",
+	"u5272":                           "\U0001f239",
+	"u5408":                           "\U0001f234",
+	"u55b6":                           "\U0001f23a",
+	"u6307":                           "\U0001f22f",
+	"u6708":                           "\U0001f237\ufe0f",
+	"u6709":                           "\U0001f236",
+	"u6e80":                           "\U0001f235",
+	"u7121":                           "\U0001f21a",
+	"u7533":                           "\U0001f238",
+	"u7981":                           "\U0001f232",
+	"u7a7a":                           "\U0001f233",
+	"uk":                              "\U0001f1ec\U0001f1e7",
+	"umbrella":                        "\u2614",
+	"unamused":                        "\U0001f612",
+	"underage":                        "\U0001f51e",
+	"unlock":                          "\U0001f513",
+	"up":                              "\U0001f199",
+	"us":                              "\U0001f1fa\U0001f1f8",
+	"v":                               "\u270c\ufe0f",
+	"vertical_traffic_light":          "\U0001f6a6",
+	"vhs":                             "\U0001f4fc",
+	"vibration_mode":                  "\U0001f4f3",
+	"video_camera":                    "\U0001f4f9",
+	"video_game":                      "\U0001f3ae",
+	"violin":                          "\U0001f3bb",
+	"virgo":                           "\u264d",
+	"volcano":                         "\U0001f30b",
+	"vs":                              "\U0001f19a",
+	"walking":                         "\U0001f6b6",
+	"waning_crescent_moon":            "\U0001f318",
+	"waning_gibbous_moon":             "\U0001f316",
+	"warning":                         "\u26a0\ufe0f",
+	"watch":                           "\u231a",
+	"water_buffalo":                   "\U0001f403",
+	"watermelon":                      "\U0001f349",
+	"wave":                            "\U0001f44b",
+	"wavy_dash":                       "\u3030\ufe0f",
+	"waxing_crescent_moon":            "\U0001f312",
+	"waxing_gibbous_moon":             "\U0001f314",
+	"wc":                              "\U0001f6be",
+	"weary":                           "\U0001f629",
+	"wedding":                         "\U0001f492",
+	"whale":                           "\U0001f433",
+	"whale2":                          "\U0001f40b",
+	"wheelchair":                      "\u267f",
+	"white_check_mark":                "\u2705",
+	"white_circle":                    "\u26aa",
+	"white_flower":                    "\U0001f4ae",
+	"white_large_square":              "\u2b1c",
+	"white_medium_small_square":       "\u25fd",
+	"white_medium_square":             "\u25fb\ufe0f",
+	"white_small_square":              "\u25ab\ufe0f",
+	"white_square_button":             "\U0001f533",
+	"wind_chime":                      "\U0001f390",
+	"wine_glass":                      "\U0001f377",
+	"wink":                            "\U0001f609",
+	"wolf":                            "\U0001f43a",
+	"woman":                           "\U0001f469",
+	"womans_clothes":                  "\U0001f45a",
+	"womans_hat":                      "\U0001f452",
+	"womens":                          "\U0001f6ba",
+	"worried":                         "\U0001f61f",
+	"wrench":                          "\U0001f527",
+	"x":                               "\u274c",
+	"yellow_heart":                    "\U0001f49b",
+	"yen":                             "\U0001f4b4",
+	"yum":                             "\U0001f60b",
+	"zap":                             "\u26a1",
+	"zero":                            "\u0030\ufe0f\u20e3",
+	"zzz":                             "\U0001f4a4",
+}
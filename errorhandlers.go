@@ -13,6 +13,7 @@ func SetupErrorMessages() {
 		http.StatusUnauthorized:        "You're not Dave! You need to Login!",
 		http.StatusInternalServerError: "Something is going horribly wrong!",
 		http.StatusBadRequest:          "You can't do that!",
+		http.StatusGone:                "That link has expired",
 	}
 }
 
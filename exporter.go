@@ -0,0 +1,154 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"flag"
+	"fmt"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/yaml.v2"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// assetReference matches links to files served from ./static/ in Source.
+var assetReference = regexp.MustCompile(`/assets/[^\s"'()<>\[\]]+`)
+
+func init() {
+	RegisterCommand("export", Command{
+		Usage: "[-drafts] [-o blog.zip]",
+		Run:   exportCommand,
+	})
+}
+
+// GetAllBlogs returns every post that is not in the trash, oldest first.
+// Unpublished posts are only included with drafts.
+func GetAllBlogs(drafts bool) []BlogPost {
+	localsession := session.Copy()
+	defer localsession.Close()
+	query := bson.M{}
+	if !drafts {
+		query["published"] = true
+	}
+	blogs := []BlogPost{}
+	localsession.DB(database).C("blogs").Find(live(query)).Sort("date").All(&blogs)
+	return blogs
+}
+
+// MarkdownFile returns the post as Markdown with YAML front matter, in the
+// form the import command reads. image is the front matter image path.
+func (post BlogPost) MarkdownFile(image string) ([]byte, error) {
+	fm := FrontMatter{
+		Title:     post.Title,
+		Date:      post.Date.UTC(),
+		Slug:      post.Slug,
+		Summary:   post.Excerpt,
+		Tags:      post.Tags,
+		Published: post.Published,
+		Unlisted:  post.Unlisted,
+		Card:      SnapCardWidth(post.Width),
+		Featured:  post.Featured,
+		Image:     image,
+	}
+	header, err := yaml.Marshal(fm)
+	if err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	out.WriteString("---\n")
+	out.Write(header)
+	out.WriteString("---\n\n")
+	out.WriteString(string(post.Source))
+	if !strings.HasSuffix(string(post.Source), "\n") {
+		out.WriteString("\n")
+	}
+	return out.Bytes(), nil
+}
+
+// WriteBlogExport writes a zip of every post as a Markdown file, with the
+// header images next to them and any other local images the posts link to
+// at their /assets/ path.
+func WriteBlogExport(w io.Writer, drafts bool) error {
+	archive := zip.NewWriter(w)
+	copied := map[string]bool{}
+
+	for _, post := range GetAllBlogs(drafts) {
+		image := ""
+		if len(post.Images) > 0 {
+			image = path.Join("images", post.Slug, path.Base(post.Images[0]))
+			// The download has started, so a missing image can only be
+			// left out.
+			if err := zipStaticFile(archive, post.Images[0], image); err != nil {
+				log.Warning(fmt.Sprintf("export %s: %s", post.Slug, err))
+				image = ""
+			}
+		}
+
+		for _, asset := range assetReference.FindAllString(string(post.Source), -1) {
+			asset = path.Clean(asset)
+			if copied[asset] || !strings.HasPrefix(asset, "/assets/") {
+				continue
+			}
+			copied[asset] = true
+			if err := zipStaticFile(archive, asset, strings.TrimPrefix(asset, "/")); err != nil {
+				log.Warning(fmt.Sprintf("export %s: %s", post.Slug, err))
+			}
+		}
+
+		content, err := post.MarkdownFile(image)
+		if err != nil {
+			return err
+		}
+		f, err := archive.Create(post.Slug + ".md")
+		if err != nil {
+			return err
+		}
+		if _, err = f.Write(content); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+// zipStaticFile copies the file served at an /assets/ URL into the archive.
+func zipStaticFile(archive *zip.Writer, url string, name string) error {
+	file, err := os.Open(path.Join("./static", path.Clean("/"+strings.TrimPrefix(url, "/assets/"))))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	f, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, file)
+	return err
+}
+
+func exportCommand(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	drafts := flags.Bool("drafts", false, "include unpublished posts")
+	output := flags.String("o", "blog.zip", "zip file to write")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err = WriteBlogExport(file, *drafts); err != nil {
+		return err
+	}
+	fmt.Printf("Exported to %s\n", *output)
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+	"time"
+)
+
+// FrontMatter is the metadata at the top of a Markdown post file, either
+// YAML between --- lines or TOML between +++ lines.
+type FrontMatter struct {
+	Title     string    `yaml:"title" toml:"title"`
+	Date      time.Time `yaml:"date" toml:"date"`
+	Slug      string    `yaml:"slug" toml:"slug"`
+	Summary   string    `yaml:"summary,omitempty" toml:"summary,omitempty"`
+	Tags      []string  `yaml:"tags" toml:"tags"`
+	Published bool      `yaml:"published" toml:"published"`
+	Unlisted  bool      `yaml:"unlisted,omitempty" toml:"unlisted,omitempty"`
+	Image     string    `yaml:"image,omitempty" toml:"image,omitempty"`
+	Card      int       `yaml:"card,omitempty" toml:"card,omitempty"`
+	Featured  bool      `yaml:"featured,omitempty" toml:"featured,omitempty"`
+}
+
+var errUnterminatedFrontMatter = errors.New("front matter is not terminated")
+
+// ParseFrontMatter splits a Markdown file into its front matter and body.
+// A file without front matter has an empty FrontMatter.
+func ParseFrontMatter(data []byte) (FrontMatter, string, error) {
+	fm := FrontMatter{}
+	data = bytes.Replace(data, []byte("\r\n"), []byte("\n"), -1)
+
+	var delim string
+	switch {
+	case bytes.HasPrefix(data, []byte("---\n")):
+		delim = "---"
+	case bytes.HasPrefix(data, []byte("+++\n")):
+		delim = "+++"
+	default:
+		return fm, string(data), nil
+	}
+
+	// Keep the newline after the opening delimiter so an empty block is
+	// found like any other.
+	rest := data[len(delim):]
+	end := bytes.Index(rest, []byte("\n"+delim+"\n"))
+	var header, body []byte
+	switch {
+	case end >= 0:
+		header, body = rest[:end+1], rest[end+len(delim)+2:]
+	case bytes.HasSuffix(rest, []byte("\n"+delim)):
+		header = rest[:len(rest)-len(delim)]
+	default:
+		return fm, "", errUnterminatedFrontMatter
+	}
+
+	var err error
+	if delim == "---" {
+		err = yaml.Unmarshal(header, &fm)
+	} else {
+		_, err = toml.Decode(string(header), &fm)
+	}
+	return fm, string(bytes.TrimLeft(body, "\n")), err
+}
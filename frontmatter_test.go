@@ -0,0 +1,38 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"testing"
+)
+
+func TestParseFrontMatter(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		title string
+		body  string
+		err   bool
+	}{
+		{"none", "Hello\n", "", "Hello\n", false},
+		{"yaml", "---\ntitle: Hi\n---\n\nHello\n", "Hi", "Hello\n", false},
+		{"toml", "+++\ntitle = \"Hi\"\n+++\nHello\n", "Hi", "Hello\n", false},
+		{"crlf", "---\r\ntitle: Hi\r\n---\r\nHello\r\n", "Hi", "Hello\n", false},
+		{"empty yaml", "---\n---\nHello\n", "", "Hello\n", false},
+		{"empty toml", "+++\n+++\nHello\n", "", "Hello\n", false},
+		{"empty at end of file", "---\n---", "", "", false},
+		{"only front matter", "---\ntitle: Hi\n---", "Hi", "", false},
+		{"unterminated", "---\ntitle: Hi\nHello\n", "", "", true},
+	}
+	for _, test := range tests {
+		fm, body, err := ParseFrontMatter([]byte(test.data))
+		if (err != nil) != test.err {
+			t.Errorf("%s: error = %v, want error %v", test.name, err, test.err)
+			continue
+		}
+		if !test.err && (fm.Title != test.title || body != test.body) {
+			t.Errorf("%s: got title %q body %q, want %q and %q", test.name, fm.Title, body, test.title, test.body)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"gopkg.in/mgo.v2/bson"
+	"io/ioutil"
+	"net/http"
+	"os/exec"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// gitSyncLock keeps webhook and command line syncs from overlapping.
+var gitSyncLock sync.Mutex
+
+func init() {
+	RegisterCommand("gitsync", Command{
+		Usage: "[-dry-run] [-pull] [dir]",
+		Run:   gitSyncCommand,
+	})
+}
+
+// gitCommit is the commit that last touched a file.
+type gitCommit struct {
+	Hash        string
+	Date        time.Time
+	AuthorEmail string
+}
+
+// git runs a git command in the working tree dir and returns its output.
+func git(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir, "-c", "core.quotepath=off"}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// gitHistory returns the last and the first commit touching every file
+// reachable from HEAD.
+func gitHistory(dir string) (last, first map[string]gitCommit, err error) {
+	out, err := git(dir, "log", "--format=%x00%H %aI %ae", "--name-only", "HEAD")
+	if err != nil {
+		return nil, nil, err
+	}
+	last = map[string]gitCommit{}
+	first = map[string]gitCommit{}
+	commit := gitCommit{}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "\x00") {
+			fields := strings.Fields(line[1:])
+			if len(fields) < 3 {
+				return nil, nil, fmt.Errorf("git log: unexpected line %q", line)
+			}
+			date, err := time.Parse(time.RFC3339, fields[1])
+			if err != nil {
+				return nil, nil, err
+			}
+			commit = gitCommit{Hash: fields[0], Date: date.UTC(), AuthorEmail: fields[2]}
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		if _, ok := last[line]; !ok {
+			last[line] = commit
+		}
+		first[line] = commit
+	}
+	return last, first, scanner.Err()
+}
+
+// ReadGitPosts reads every Markdown file in the tree at HEAD of the git
+// working tree dir. Posts are dated by the commit that added the file and
+// written by the author of the commit that last changed it, if they have an
+// account, otherwise by fallback.
+func ReadGitPosts(dir string, fallback bson.ObjectId) ([]ImportedPost, error) {
+	out, err := git(dir, "ls-tree", "-r", "--name-only", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	last, first, err := gitHistory(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	authors := map[string]bson.ObjectId{}
+	posts := []ImportedPost{}
+	for _, file := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if path.Ext(file) != ".md" {
+			continue
+		}
+		data, err := git(dir, "show", "HEAD:"+file)
+		if err != nil {
+			return nil, err
+		}
+		in := ImportedPost{GitPath: file, Commit: last[file].Hash}
+		in.FrontMatter, in.Source, err = ParseFrontMatter(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", file, err)
+		}
+
+		in.Slug = Slugify(in.Slug)
+		if in.Slug == "" {
+			in.Slug = Slugify(strings.TrimSuffix(path.Base(file), ".md"))
+		}
+		if in.Date.IsZero() {
+			in.Date = first[file].Date
+		}
+		if in.Image != "" {
+			image := path.Clean(path.Join(path.Dir(file), in.Image))
+			in.ImageName = path.Base(image)
+			in.ImageData, err = git(dir, "show", "HEAD:"+image)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s", file, err)
+			}
+		}
+
+		email := last[file].AuthorEmail
+		author, ok := authors[email]
+		if !ok {
+			author = fallback
+			if u, err := GetUserByEmail(email); err == nil && (u.IsBlogAuthor || u.IsAdmin) {
+				author = u.Id
+			}
+			authors[email] = author
+		}
+		in.Author = author
+		posts = append(posts, in)
+	}
+	return posts, nil
+}
+
+// SyncGitPosts upserts the posts in the git working tree dir and
+// unpublishes the posts whose file is no longer there. With pull, the tree
+// is fast-forwarded first.
+func SyncGitPosts(dir string, pull bool, dryRun bool) (map[string]int, error) {
+	gitSyncLock.Lock()
+	defer gitSyncLock.Unlock()
+
+	if pull {
+		if _, err := git(dir, "pull", "--ff-only"); err != nil {
+			return nil, err
+		}
+	}
+
+	fallback := bson.ObjectId("")
+	if config.Git.Author != "" {
+		u, err := GetUserByName(config.Git.Author)
+		if err != nil {
+			return nil, fmt.Errorf("unknown author %q", config.Git.Author)
+		}
+		fallback = u.Id
+	}
+
+	posts, err := ReadGitPosts(dir, fallback)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	files := []string{}
+	for _, in := range posts {
+		files = append(files, in.GitPath)
+		if in.Author == "" {
+			err = fmt.Errorf("no account for %s and no fallback author", in.GitPath)
+		} else {
+			var action string
+			action, err = in.Upsert(dryRun)
+			if err == nil {
+				log.Info(fmt.Sprintf("gitsync: %s %s -> %s", action, in.GitPath, in.Slug))
+				counts[action]++
+				continue
+			}
+		}
+		log.Warning(fmt.Sprintf("gitsync: %s: %s", in.GitPath, err))
+		counts["error"]++
+	}
+
+	localsession := session.Copy()
+	defer localsession.Close()
+	removed := []BlogPost{}
+	err = localsession.DB(database).C("blogs").Find(live(bson.M{
+		"gitpath":   bson.M{"$exists": true, "$nin": files},
+		"published": true,
+	})).All(&removed)
+	if err != nil {
+		return counts, err
+	}
+	for _, post := range removed {
+		if !dryRun {
+			if err := post.Transition(StateDraft, systemUser, "Removed from git"); err != nil {
+				log.Warning(fmt.Sprintf("gitsync: %s: %s", post.GitPath, err))
+				counts["error"]++
+				continue
+			}
+		}
+		log.Info(fmt.Sprintf("gitsync: %s %s -> %s", ImportUnpublished, post.GitPath, post.Slug))
+		counts[ImportUnpublished]++
+	}
+	return counts, nil
+}
+
+// GitWebhookHandler syncs the git working tree when the repository host
+// reports a push. The request must be signed with Git.WebhookSecret the way
+// GitHub and Gitea sign them.
+func GitWebhookHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) error {
+	if config.Git.Dir == "" || config.Git.WebhookSecret == "" {
+		return NotFoundHandler(w, req, ctx, pjax)
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, 1<<20))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return nil
+	}
+	if !validWebhookSignature(body, req.Header.Get("X-Hub-Signature-256")) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, "bad signature")
+		return nil
+	}
+
+	go func() {
+		counts, err := SyncGitPosts(config.Git.Dir, true, false)
+		if err != nil {
+			log.Warning(fmt.Sprintf("gitsync: %s", err))
+			return
+		}
+		log.Info(fmt.Sprintf("gitsync: %d created, %d updated, %d unpublished, %d failed",
+			counts[ImportCreated], counts[ImportUpdated], counts[ImportUnpublished], counts["error"]))
+	}()
+	w.WriteHeader(http.StatusAccepted)
+	fmt.Fprint(w, "sync started")
+	return nil
+}
+
+func validWebhookSignature(body []byte, signature string) bool {
+	sum, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil || !strings.HasPrefix(signature, "sha256=") {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(config.Git.WebhookSecret))
+	mac.Write(body)
+	return hmac.Equal(sum, mac.Sum(nil))
+}
+
+func gitSyncCommand(args []string) error {
+	flags := flag.NewFlagSet("gitsync", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "report what would change without writing anything")
+	pull := flags.Bool("pull", false, "fast-forward the working tree before syncing")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	dir := config.Git.Dir
+	if flags.NArg() == 1 {
+		dir = flags.Arg(0)
+	}
+	if dir == "" || flags.NArg() > 1 {
+		return errors.New("usage: gitsync " + commands["gitsync"].Usage)
+	}
+
+	counts, err := SyncGitPosts(dir, *pull, *dryRun)
+	if err != nil {
+		return err
+	}
+	prefix := ""
+	if *dryRun {
+		prefix = "dry run: "
+	}
+	fmt.Printf("%s%d created, %d updated, %d unchanged, %d unpublished, %d failed\n", prefix,
+		counts[ImportCreated], counts[ImportUpdated], counts[ImportUnchanged], counts[ImportUnpublished], counts["error"])
+	if counts["error"] > 0 {
+		return fmt.Errorf("%d files could not be synced", counts["error"])
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"github.com/alecthomas/chroma"
+	chromahtml "github.com/alecthomas/chroma/formatters/html"
+	"github.com/alecthomas/chroma/lexers"
+	"github.com/alecthomas/chroma/styles"
+	"io"
+)
+
+// DefaultCodeTheme is used when the configured theme is unknown.
+const DefaultCodeTheme = "github"
+
+// highlighter emits class based markup, the colors live in the stylesheet
+// served for the configured theme.
+var highlighter = chromahtml.New(chromahtml.WithClasses(true))
+
+// HighlightCode writes code as highlighted HTML. It returns false without
+// writing anything if there is no lexer for lang.
+func HighlightCode(w io.Writer, code string, lang string) bool {
+	if lang == "" {
+		return false
+	}
+	lexer := lexers.Get(lang)
+	if lexer == nil {
+		return false
+	}
+	iterator, err := chroma.Coalesce(lexer).Tokenise(nil, code)
+	if err != nil {
+		log.Error(err.Error())
+		return false
+	}
+	return highlighter.Format(w, styles.Fallback, iterator) == nil
+}
+
+// CodeThemeExists reports whether a highlighting theme with this name exists.
+func CodeThemeExists(theme string) bool {
+	_, ok := styles.Registry[theme]
+	return ok
+}
+
+// CodeTheme returns the configured highlighting theme.
+func CodeTheme() string {
+	if CodeThemeExists(config.Site.CodeTheme) {
+		return config.Site.CodeTheme
+	}
+	return DefaultCodeTheme
+}
+
+// WriteCodeThemeCSS writes the stylesheet for a highlighting theme.
+func WriteCodeThemeCSS(w io.Writer, theme string) error {
+	return highlighter.WriteCSS(w, styles.Get(theme))
+}
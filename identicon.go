@@ -0,0 +1,51 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+const (
+	IdenticonCells = 5
+	IdenticonSize  = 420
+)
+
+// GenerateIdenticon draws the horizontally symmetric identicon for an md5
+// hex hash, like the ones returned by User.GetInfoHash.
+func GenerateIdenticon(hash string) (image.Image, error) {
+	sum, err := hex.DecodeString(hash)
+	if err != nil {
+		return nil, err
+	}
+	if len(sum) != md5.Size {
+		return nil, errors.New("identicon hash must be an md5 sum")
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, IdenticonSize, IdenticonSize))
+	draw.Draw(img, img.Bounds(), &image.Uniform{color.RGBA{0xf0, 0xf0, 0xf0, 0xff}}, image.ZP, draw.Src)
+	fill := &image.Uniform{color.RGBA{sum[0], sum[1], sum[2], 0xff}}
+
+	cell := IdenticonSize / IdenticonCells
+	margin := (IdenticonSize - cell*IdenticonCells) / 2
+	half := (IdenticonCells + 1) / 2
+	for x := 0; x < half; x++ {
+		for y := 0; y < IdenticonCells; y++ {
+			bit := x*IdenticonCells + y
+			if sum[3+bit/8]&(1<<uint(bit%8)) == 0 {
+				continue
+			}
+			for _, col := range []int{x, IdenticonCells - 1 - x} {
+				r := image.Rect(margin+col*cell, margin+y*cell, margin+(col+1)*cell, margin+(y+1)*cell)
+				draw.Draw(img, r, fill, image.ZP, draw.Src)
+			}
+		}
+	}
+	return img, nil
+}
@@ -0,0 +1,267 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"crypto/sha1"
+	"errors"
+	"flag"
+	"fmt"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"html/template"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// The outcomes of upserting an ImportedPost.
+const (
+	ImportCreated     = "create"
+	ImportUpdated     = "update"
+	ImportUnchanged   = "unchanged"
+	ImportUnpublished = "unpublish"
+)
+
+func init() {
+	RegisterCommand("import", Command{
+		Usage: "[-dry-run] -author username dir",
+		Run:   importCommand,
+	})
+}
+
+// ImportedPost is a post read from outside the site. It is matched to an
+// existing post by slug, so importing the same post again updates it.
+// Posts synced from git also carry their path and last commit.
+type ImportedPost struct {
+	FrontMatter
+	Source    string
+	Author    bson.ObjectId
+	ImageName string
+	ImageData []byte
+	GitPath   string
+	Commit    string
+}
+
+// Upsert creates or updates the post with the same slug, and reports which
+// it did. With dryRun nothing is written.
+func (in ImportedPost) Upsert(dryRun bool) (string, error) {
+	if in.Title == "" {
+		return "", errors.New("missing title")
+	}
+	if in.Slug == "" {
+		return "", errors.New("missing slug")
+	}
+
+	post, err := GetBlogPostWithSlug(in.Slug)
+	exists := err == nil
+	if err != nil && err != mgo.ErrNotFound {
+		return "", err
+	}
+	// Git only manages the posts it created.
+	if exists && in.GitPath != "" && post.GitPath == "" {
+		return "", fmt.Errorf("a post with slug %q was not synced from git, rename one of them", in.Slug)
+	}
+	// Creating the post again would leave two posts with the slug once the
+	// trashed one is restored.
+	if !exists {
+		trashed, err := SlugInTrash(in.Slug)
+		if err != nil {
+			return "", err
+		}
+		if trashed {
+			return "", fmt.Errorf("a post with slug %q is in the trash, restore or purge it first", in.Slug)
+		}
+	}
+
+	imageSha1 := ""
+	if in.ImageData != nil {
+		imageSha1 = fmt.Sprintf("%x", sha1.Sum(in.ImageData))
+	}
+	imageChanged := imageSha1 != "" && (len(post.Images) == 0 || !strings.Contains(post.Images[0], "/"+imageSha1))
+
+	author := getUserById(in.Author)
+	edited := post.Title != in.Title ||
+		string(post.Source) != in.Source ||
+		post.Excerpt != in.Summary
+	state := post
+	state.ImportState(in.Published, edited, author)
+
+	changed := edited ||
+		state.CurrentState() != post.CurrentState() ||
+		post.Unlisted != in.Unlisted ||
+		(in.Card != 0 && post.Width != SnapCardWidth(in.Card)) ||
+		post.Featured != in.Featured ||
+		(!in.Date.IsZero() && !post.Date.Equal(in.Date)) ||
+		!equalStrings(post.Tags, in.Tags) ||
+		post.GitPath != in.GitPath ||
+		(in.Commit != "" && post.Commit != in.Commit) ||
+		imageChanged
+
+	action := ImportUpdated
+	switch {
+	case !exists:
+		action = ImportCreated
+	case !changed:
+		return ImportUnchanged, nil
+	}
+	if dryRun {
+		return action, nil
+	}
+
+	now := time.Now().UTC()
+	if !exists {
+		post = BlogPost{
+			Id:         bson.NewObjectId(),
+			Slug:       in.Slug,
+			Author:     in.Author,
+			EditedBy:   in.Author,
+			DateEdited: now,
+			Date:       now,
+		}
+	}
+	post.Title = in.Title
+	post.Source = template.HTML(in.Source)
+	post.Excerpt = in.Summary
+	post.ImportState(in.Published, edited, author)
+	post.Unlisted = in.Unlisted
+	post.Featured = in.Featured
+	if in.Card != 0 || !exists {
+		post.Width = SnapCardWidth(in.Card)
+	}
+	post.Tags = in.Tags
+	post.GitPath = in.GitPath
+	if in.Commit != "" {
+		post.Commit = in.Commit
+	}
+	if !in.Date.IsZero() {
+		post.Date = in.Date.UTC()
+	}
+	if imageChanged {
+		post.Images, err = ProcessBlogImage(post.Id, in.ImageName, in.ImageData)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if exists {
+		post.Edited = true
+		post.DateEdited = now
+		post.EditedBy = in.Author
+		return action, post.Store()
+	}
+
+	post.Render()
+	localsession := session.Copy()
+	defer localsession.Close()
+	if err = localsession.DB(database).C("blogs").Insert(post); err != nil {
+		return "", err
+	}
+	ScheduleRelatedRefresh()
+	return action, nil
+}
+
+// ReadMarkdownPost reads a Markdown file with front matter. The image in
+// the front matter is relative to the file, the slug defaults to the file
+// name and the date to the file's modification time. Slugs are made URL
+// safe like the slugs of posts written on the site.
+func ReadMarkdownPost(path string) (ImportedPost, error) {
+	in := ImportedPost{}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return in, err
+	}
+	in.FrontMatter, in.Source, err = ParseFrontMatter(data)
+	if err != nil {
+		return in, err
+	}
+
+	in.Slug = Slugify(in.Slug)
+	if in.Slug == "" {
+		in.Slug = Slugify(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+	}
+	if in.Date.IsZero() {
+		info, err := os.Stat(path)
+		if err != nil {
+			return in, err
+		}
+		in.Date = info.ModTime().UTC().Truncate(time.Second)
+	}
+	if in.Image != "" {
+		image := in.Image
+		if !filepath.IsAbs(image) {
+			image = filepath.Join(filepath.Dir(path), image)
+		}
+		in.ImageName = filepath.Base(image)
+		in.ImageData, err = ioutil.ReadFile(image)
+		if err != nil {
+			return in, err
+		}
+	}
+	return in, nil
+}
+
+func importCommand(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "report what would change without writing anything")
+	authorName := flags.String("author", "", "username of the author of imported posts")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 || *authorName == "" {
+		return errors.New("usage: import " + commands["import"].Usage)
+	}
+
+	author, err := GetUserByName(*authorName)
+	if err != nil {
+		return fmt.Errorf("unknown author %q", *authorName)
+	}
+
+	files, err := filepath.Glob(filepath.Join(flags.Arg(0), "*.md"))
+	if err != nil {
+		return err
+	}
+
+	counts := map[string]int{}
+	for _, file := range files {
+		in, err := ReadMarkdownPost(file)
+		action := ""
+		if err == nil {
+			in.Author = author.Id
+			action, err = in.Upsert(*dryRun)
+		}
+		if err != nil {
+			fmt.Printf("%-9s %s: %s\n", "error", file, err)
+			counts["error"]++
+			continue
+		}
+		fmt.Printf("%-9s %s -> %s\n", action, file, in.Slug)
+		counts[action]++
+	}
+
+	prefix := ""
+	if *dryRun {
+		prefix = "dry run: "
+	}
+	fmt.Printf("%s%d created, %d updated, %d unchanged, %d failed\n", prefix,
+		counts[ImportCreated], counts[ImportUpdated], counts[ImportUnchanged], counts["error"])
+	if counts["error"] > 0 {
+		return fmt.Errorf("%d files could not be imported", counts["error"])
+	}
+	return nil
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
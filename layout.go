@@ -0,0 +1,174 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"strconv"
+)
+
+// The card sizes an author can choose, in columns of the 12 column grid.
+const (
+	CardSmall  = 4
+	CardMedium = 6
+	CardLarge  = 8
+	CardFull   = 12
+)
+
+// GridColumns is the width of a row of cards.
+const GridColumns = 12
+
+// FeaturedMinWidth is the smallest a featured post's card is shown.
+const FeaturedMinWidth = CardLarge
+
+// layoutLookahead is how many of the following cards are considered when
+// looking for ones that fill a row exactly.
+const layoutLookahead = 8
+
+var CardSizes = []struct {
+	Width int
+	Name  string
+}{
+	{CardSmall, "Small"},
+	{CardMedium, "Medium"},
+	{CardLarge, "Large"},
+	{CardFull, "Full width"},
+}
+
+// ParseCardWidth reads a card size from a form, defaulting to CardSmall.
+func ParseCardWidth(s string) int {
+	width, _ := strconv.Atoi(s)
+	for _, size := range CardSizes {
+		if size.Width == width {
+			return width
+		}
+	}
+	return CardSmall
+}
+
+// SnapCardWidth rounds a width up to the next card size. Posts from before
+// authors chose a size have a random width.
+func SnapCardWidth(width int) int {
+	if width <= 0 {
+		return CardSmall
+	}
+	for _, size := range CardSizes {
+		if width <= size.Width {
+			return size.Width
+		}
+	}
+	return CardFull
+}
+
+// CardSize is the card size the author chose.
+func (post BlogPost) CardSize() int {
+	return SnapCardWidth(post.Width)
+}
+
+// CardWidth is the number of columns the post's card takes up.
+func (post BlogPost) CardWidth() int {
+	width := post.CardSize()
+	if post.Featured && width < FeaturedMinWidth {
+		width = FeaturedMinWidth
+	}
+	return width
+}
+
+// Card is a post placed in a row of the grid.
+type Card struct {
+	Post  BlogPost
+	Width int
+}
+
+// CardRow is a row of cards that adds up to GridColumns.
+type CardRow struct {
+	Cards []Card
+}
+
+// LayoutCards packs posts into rows of the grid without gaps. Featured
+// posts come first, otherwise the order is kept as far as possible: every
+// row starts with the first card not placed yet and is filled with the
+// earliest following cards that fit exactly. Rows that can't be filled
+// exactly have their cards widened. The layout only depends on the posts,
+// so a page always looks the same.
+func LayoutCards(posts []BlogPost) []CardRow {
+	queue := []Card{}
+	for _, featured := range []bool{true, false} {
+		for _, post := range posts {
+			if post.Featured == featured {
+				queue = append(queue, Card{Post: post, Width: post.CardWidth()})
+			}
+		}
+	}
+
+	rows := []CardRow{}
+	for len(queue) > 0 {
+		window := len(queue)
+		if window > layoutLookahead {
+			window = layoutLookahead
+		}
+		picked, ok := fillRow(queue[:window], 1, []int{0}, GridColumns-queue[0].Width)
+		if !ok {
+			picked = firstFit(queue[:window])
+		}
+
+		row := CardRow{}
+		used := 0
+		taken := map[int]bool{}
+		for _, i := range picked {
+			row.Cards = append(row.Cards, queue[i])
+			used += queue[i].Width
+			taken[i] = true
+		}
+		stretch(row.Cards, GridColumns-used)
+		rows = append(rows, row)
+
+		rest := []Card{}
+		for i, card := range queue {
+			if !taken[i] {
+				rest = append(rest, card)
+			}
+		}
+		queue = rest
+	}
+	return rows
+}
+
+// fillRow looks for cards from start on that add up to exactly space,
+// preferring earlier cards, and returns their indexes added to picked.
+func fillRow(cards []Card, start int, picked []int, space int) ([]int, bool) {
+	if space == 0 {
+		return picked, true
+	}
+	for i := start; i < len(cards); i++ {
+		if cards[i].Width <= space {
+			if found, ok := fillRow(cards, i+1, append(picked, i), space-cards[i].Width); ok {
+				return found, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// firstFit picks the first card and every following one that still fits.
+func firstFit(cards []Card) []int {
+	picked := []int{0}
+	space := GridColumns - cards[0].Width
+	for i := 1; i < len(cards); i++ {
+		if cards[i].Width <= space {
+			picked = append(picked, i)
+			space -= cards[i].Width
+		}
+	}
+	return picked
+}
+
+// stretch shares out the columns left in a row between its cards.
+func stretch(cards []Card, space int) {
+	for i := range cards {
+		cards[i].Width += space / len(cards)
+		if i < space%len(cards) {
+			cards[i].Width++
+		}
+	}
+}
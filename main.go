@@ -51,6 +51,90 @@ func setupLog(logBackend *logging.LogBackend) {
 	logging.SetFormatter(logging.MustStringFormatter(format))
 }
 
+func setupRouter() {
+	router = mux.NewRouter()
+	router.StrictSlash(true)
+	router.NotFoundHandler = handler(NotFoundHandler)
+
+	router.Path("/").Handler(handler(IndexPageHandler)).Name("index").Methods("GET")
+	router.Path("/bio").Handler(handler(BioPageHandler)).Name("bio").Methods("GET")
+	router.Path("/sticker").Handler(handler(StickerPageHandler)).Name("sticker").Methods("GET")
+	router.Path("/blog").Handler(handler(BlogIndexHandler)).Name("blog").Methods("GET")
+	router.Path("/clock").Handler(handler(ClockPageHandler)).Name("clock").Methods("GET")
+
+	router.Path("/blog/write").Handler(handler(BlogWriteFormHandler)).Name("blog-write").Methods("GET")
+	router.Path("/blog/write").Handler(handler(BlogWriteHandler)).Methods("POST")
+
+	router.Path("/blog/trash").Handler(handler(BlogTrashHandler)).Name("blog-trash").Methods("GET")
+	router.Path("/blog/delete").Name("blog-delete")
+	router.Path("/blog/delete/{id}").Handler(handler(BlogDeleteHandler)).Methods("POST")
+	router.Path("/blog/restore").Name("blog-restore")
+	router.Path("/blog/restore/{id}").Handler(handler(BlogRestoreHandler)).Methods("POST")
+	router.Path("/blog/purge").Name("blog-purge")
+	router.Path("/blog/purge/{id}").Handler(handler(BlogPurgeHandler)).Methods("POST")
+
+	router.Path("/blog/reviews").Handler(handler(BlogReviewQueueHandler)).Name("blog-reviews").Methods("GET")
+	router.Path("/blog/review").Name("blog-review")
+	router.Path("/blog/review/{id}").Handler(handler(BlogReviewHandler)).Methods("GET")
+	router.Path("/blog/transition").Name("blog-transition")
+	router.Path("/blog/transition/{id}").Handler(handler(BlogTransitionHandler)).Methods("POST")
+	router.Path("/blog/comment").Name("blog-comment")
+	router.Path("/blog/comment/{id}").Handler(handler(BlogCommentHandler)).Methods("POST")
+
+	router.Path("/blog/pin").Name("blog-pin")
+	router.Path("/blog/pin/{id}").Handler(handler(BlogPinHandler)).Methods("POST")
+
+	router.Path("/blog/share").Name("blog-share")
+	router.Path("/blog/share/{id}").Handler(handler(BlogShareHandler)).Methods("POST")
+	router.Path("/blog/preview").Name("blog-preview")
+	router.Path("/blog/preview/{token}").Handler(handler(BlogPreviewHandler)).Methods("GET")
+
+	router.Path("/blog/export").Handler(handler(BlogExportHandler)).Name("blog-export").Methods("GET")
+
+	router.Path("/blog/autosave").Handler(handler(AutosaveHandler)).Name("blog-autosave").Methods("GET", "POST")
+	router.Path("/blog/autosave/discard").Handler(handler(AutosaveDiscardHandler)).Name("blog-autosave-discard").Methods("POST")
+
+	router.Path("/blog/render").Handler(handler(BlogRenderHandler)).Name("blog-render").Methods("POST")
+
+	router.Path("/blog/gitsync").Handler(handler(GitWebhookHandler)).Name("blog-gitsync").Methods("POST")
+
+	router.Path("/blog/edit").Handler(handler(BlogEditHandler)).Name("blog-edit").Methods("POST")
+	router.Path("/blog/edit/{id}").Handler(handler(BlogEditFormHandler)).Methods("GET")
+
+	router.Path("/blog/read").Name("blog-read")
+	router.Path("/blog/read/{slug}").Handler(handler(BlogReadHandler)).Methods("GET")
+
+	router.Path("/blog/static").Name("blog-static")
+	router.Path("/blog/static/{id}").Handler(handler(BlogStaticHandler)).Methods("GET")
+
+	router.Path("/blog/series").Handler(handler(SeriesWriteFormHandler)).Name("series-write").Methods("GET")
+	router.Path("/blog/series").Handler(handler(SeriesWriteHandler)).Methods("POST")
+	router.Path("/blog/series/delete").Name("series-delete")
+	router.Path("/blog/series/delete/{id}").Handler(handler(SeriesDeleteHandler)).Methods("POST")
+
+	router.Path("/series").Handler(handler(SeriesIndexHandler)).Name("series").Methods("GET")
+	router.Path("/series/{slug}").Handler(handler(SeriesPageHandler)).Methods("GET")
+
+	router.Path("/author").Name("author")
+	router.Path("/author/{username}").Handler(handler(AuthorPageHandler)).Methods("GET")
+
+	router.Path("/identicon").Name("identicon")
+	router.Path("/identicon/{hash}").Handler(handler(IdenticonHandler)).Methods("GET")
+	router.Path("/oembed").Handler(handler(OEmbedHandler)).Name("oembed").Methods("GET")
+	router.Path("/card").Name("card")
+	router.Path("/card/{name}.png").Handler(handler(SocialCardHandler)).Methods("GET")
+
+	router.Path("/highlight/{theme}.css").Handler(handler(CodeThemeHandler)).Name("code-theme").Methods("GET")
+
+	router.Path("/login").Handler(handler(LoginHandler)).Name("login").Methods("POST")
+	router.Path("/login").Handler(handler(LoginFormHandler)).Methods("GET")
+	router.Path("/register").Handler(handler(RegisterHandler)).Name("register").Methods("POST")
+	router.Path("/register").Handler(handler(RegisterFormHandler)).Methods("GET")
+	router.Path("/logout").Handler(handler(LogoutHandler)).Name("logout").Methods("GET")
+
+	router.PathPrefix("/assets/").Handler(http.StripPrefix("/assets/", http.FileServer(http.Dir("./static/"))))
+}
+
 func main() {
 	runtime.GOMAXPROCS(runtime.NumCPU())
 	logBackend := logging.NewLogBackend(os.Stdout, "", 0)
@@ -74,6 +158,9 @@ func main() {
 	}); err != nil {
 		log.Fatal(err)
 	}
+	if err := EnsureViewIndexes(); err != nil {
+		log.Fatal(err)
+	}
 
 	REGEX_EMAIL, err = regexp.Compile(`^[_a-z0-9-]+(\.[_a-z0-9-]+)*@[a-z0-9-]+(\.[a-z0-9-]+)*(\.[a-z]{2,3})$`)
 	if err != nil {
@@ -87,39 +174,21 @@ func main() {
 
 	store = sessions.NewCookieStore([]byte(config.Server.Secret))
 
-	router = mux.NewRouter()
-	router.StrictSlash(true)
-	router.NotFoundHandler = handler(NotFoundHandler)
-
-	router.Path("/").Handler(handler(IndexPageHandler)).Name("index").Methods("GET")
-	router.Path("/bio").Handler(handler(BioPageHandler)).Name("bio").Methods("GET")
-	router.Path("/sticker").Handler(handler(StickerPageHandler)).Name("sticker").Methods("GET")
-	router.Path("/blog").Handler(handler(BlogIndexHandler)).Name("blog").Methods("GET")
-	router.Path("/clock").Handler(handler(ClockPageHandler)).Name("clock").Methods("GET")
-
-	router.Path("/blog/write").Handler(handler(BlogWriteFormHandler)).Name("blog-write").Methods("GET")
-	router.Path("/blog/write").Handler(handler(BlogWriteHandler)).Methods("POST")
-
-	router.Path("/blog/edit").Name("blog-edit")
-	// router.Path("/blog/edit/{id}").Handler(handler(BlogEditFormHandler)).Methods("GET")
-	// router.Path("/blog/edit").Handler(handler(BlogEditHandler)).Methods("POST")
-
-	router.Path("/blog/read").Name("blog-read")
-	router.Path("/blog/read/{slug}").Handler(handler(BlogReadHandler)).Methods("GET")
-
-	router.Path("/blog/static").Name("blog-static")
-	router.Path("/blog/static/{id}").Handler(handler(BlogStaticHandler)).Methods("GET")
-
-	router.Path("/login").Handler(handler(LoginHandler)).Name("login").Methods("POST")
-	router.Path("/login").Handler(handler(LoginFormHandler)).Methods("GET")
-	router.Path("/register").Handler(handler(RegisterHandler)).Name("register").Methods("POST")
-	router.Path("/register").Handler(handler(RegisterFormHandler)).Methods("GET")
-	router.Path("/logout").Handler(handler(LogoutHandler)).Name("logout").Methods("GET")
-
-	router.PathPrefix("/assets/").Handler(http.StripPrefix("/assets/", http.FileServer(http.Dir("./static/"))))
+	setupRouter()
 
 	os.MkdirAll("./static/img/blog/", os.ModeDir)
 
+	if len(os.Args) > 1 {
+		err := RunCommand(os.Args[1], os.Args[2:])
+		if flushErr := FlushRelatedRefresh(); err == nil {
+			err = flushErr
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	log.Info(fmt.Sprintf("Listening on %s", config.Server.Address))
 	AccessLog = &lumberjack.Logger{
 		Filename:   "logs/access.log",
@@ -128,6 +197,11 @@ func main() {
 	}
 	defer AccessLog.Close()
 
+	go PurgeTrashLoop()
+	go ViewRankingLoop()
+	go RelatedPostsLoop()
+	ScheduleRelatedRefresh()
+
 	err = LoadStats()
 	if err != nil {
 		panic(err)
@@ -0,0 +1,205 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/russross/blackfriday"
+	"html"
+	"html/template"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// MoreMarker separates the excerpt of a post from the rest of its Source.
+const MoreMarker = "<!--more-->"
+
+// SummaryLength is the number of characters a generated summary is cut to.
+const SummaryLength = 256
+
+// WordsPerMinute is the reading speed used to estimate reading time.
+const WordsPerMinute = 200
+
+// A table of contents is only shown on posts with at least this many
+// headings that take at least this many minutes to read.
+const (
+	TocMinHeadings = 3
+	TocMinMinutes  = 4
+)
+
+// HeadingIdPrefix namespaces the anchors of headings in posts so they
+// can't clash with the ids of the page around the post.
+const HeadingIdPrefix = "h-"
+
+const markdownHtmlFlags = blackfriday.HTML_USE_XHTML |
+	blackfriday.HTML_USE_SMARTYPANTS |
+	blackfriday.HTML_SMARTYPANTS_FRACTIONS |
+	blackfriday.HTML_SMARTYPANTS_DASHES |
+	blackfriday.HTML_SMARTYPANTS_LATEX_DASHES
+
+const markdownExtensions = blackfriday.EXTENSION_NO_INTRA_EMPHASIS |
+	blackfriday.EXTENSION_TABLES |
+	blackfriday.EXTENSION_FENCED_CODE |
+	blackfriday.EXTENSION_AUTOLINK |
+	blackfriday.EXTENSION_STRIKETHROUGH |
+	blackfriday.EXTENSION_SPACE_HEADERS |
+	blackfriday.EXTENSION_HEADER_IDS |
+	blackfriday.EXTENSION_BACKSLASH_LINE_BREAK |
+	blackfriday.EXTENSION_DEFINITION_LISTS
+
+var markdownPolicy = newMarkdownPolicy()
+
+func newMarkdownPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("id").Matching(bluemonday.Paragraph).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^chroma$`)).OnElements("pre")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^[a-z0-9]+$`)).OnElements("span")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^emoji$`)).OnElements("img")
+	p.AllowAttrs("alt", "title").Matching(regexp.MustCompile(`^[^<>]*$`)).OnElements("img")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^embed embed-[a-z]+$`)).OnElements("a")
+	return p
+}
+
+// Heading is an entry in the table of contents of a post.
+type Heading struct {
+	Level int
+	Text  string
+	Id    string
+}
+
+// markdownRenderer is the blackfriday HTML renderer with stable heading
+// anchors that records every heading it renders.
+type markdownRenderer struct {
+	blackfriday.Renderer
+	toc []Heading
+	ids map[string]bool
+}
+
+func newMarkdownRenderer() *markdownRenderer {
+	return &markdownRenderer{
+		Renderer: blackfriday.HtmlRenderer(markdownHtmlFlags, "", ""),
+		ids:      make(map[string]bool),
+	}
+}
+
+func (r *markdownRenderer) Header(out *bytes.Buffer, text func() bool, level int, id string) {
+	marker := out.Len()
+	if marker > 0 {
+		out.WriteByte('\n')
+	}
+	start := out.Len()
+	if !text() {
+		out.Truncate(marker)
+		return
+	}
+	inner := string(out.Bytes()[start:])
+	out.Truncate(start)
+
+	title := PlainText(template.HTML(inner))
+	if id == "" {
+		id = title
+	}
+	id = r.uniqueId(Slugify(id))
+	r.toc = append(r.toc, Heading{Level: level, Text: title, Id: id})
+
+	fmt.Fprintf(out, "<h%d id=\"%s\">%s</h%d>\n", level, id, inner, level)
+}
+
+func (r *markdownRenderer) BlockCode(out *bytes.Buffer, text []byte, info string) {
+	lang := ""
+	if fields := strings.Fields(info); len(fields) > 0 {
+		lang = fields[0]
+	}
+	var highlighted bytes.Buffer
+	if !HighlightCode(&highlighted, string(text), lang) {
+		r.Renderer.BlockCode(out, text, info)
+		return
+	}
+	if out.Len() > 0 {
+		out.WriteByte('\n')
+	}
+	out.Write(highlighted.Bytes())
+	out.WriteByte('\n')
+}
+
+func (r *markdownRenderer) uniqueId(id string) string {
+	if id == "" {
+		id = "section"
+	}
+	// A heading may itself read like a suffixed one, so try suffixes until
+	// one is free.
+	unique := id
+	for n := 1; r.ids[unique]; n++ {
+		unique = fmt.Sprintf("%s-%d", id, n)
+	}
+	r.ids[unique] = true
+	return HeadingIdPrefix + unique
+}
+
+// RenderMarkdown renders Markdown source into sanitized HTML.
+func RenderMarkdown(source string) template.HTML {
+	content, _ := RenderMarkdownWithToc(source)
+	return content
+}
+
+// RenderMarkdownWithToc renders Markdown source into sanitized HTML and
+// returns the headings found in it, in document order.
+func RenderMarkdownWithToc(source string) (template.HTML, []Heading) {
+	return renderMarkdown(ExpandShortcodes(source))
+}
+
+// RenderMarkdownText renders Markdown source without running its
+// shortcodes, and returns its text.
+func RenderMarkdownText(source string) string {
+	content, _ := renderMarkdown(StripShortcodes(source))
+	return PlainText(content)
+}
+
+func renderMarkdown(source string) (template.HTML, []Heading) {
+	renderer := newMarkdownRenderer()
+	unsafe := blackfriday.Markdown([]byte(source), renderer, markdownExtensions)
+	unsafe = ReplaceEmoji(unsafe)
+	return template.HTML(markdownPolicy.SanitizeBytes(unsafe)), renderer.toc
+}
+
+// CountWords returns the number of words in rendered HTML.
+func CountWords(content template.HTML) int {
+	return len(strings.Fields(PlainText(content)))
+}
+
+// ReadingTime estimates the minutes needed to read the given number of words.
+func ReadingTime(words int) int {
+	minutes := (words + WordsPerMinute - 1) / WordsPerMinute
+	if minutes < 1 {
+		return 1
+	}
+	return minutes
+}
+
+// PlainText strips all markup from rendered HTML and collapses whitespace.
+func PlainText(content template.HTML) string {
+	stripped := bluemonday.StrictPolicy().Sanitize(string(content))
+	return strings.Join(strings.Fields(html.UnescapeString(stripped)), " ")
+}
+
+// TruncateWords cuts s to at most n characters without splitting a word.
+func TruncateWords(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	cut := string(runes[:n])
+	if !unicode.IsSpace(runes[n]) {
+		if i := strings.LastIndexFunc(cut, unicode.IsSpace); i > 0 {
+			cut = cut[:i]
+		}
+	}
+	cut = strings.TrimRightFunc(cut, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	})
+	return cut + "…"
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHeadingIds(t *testing.T) {
+	tests := []struct {
+		source string
+		want   []string
+	}{
+		{"# Hello\n\n# World\n", []string{"h-hello", "h-world"}},
+		{"# Hello\n\n# Hello\n\n# Hello\n", []string{"h-hello", "h-hello-1", "h-hello-2"}},
+		{"# Hello\n\n# Hello\n\n# Hello-1\n", []string{"h-hello", "h-hello-1", "h-hello-1-1"}},
+		{"# Hello-1\n\n# Hello\n\n# Hello\n", []string{"h-hello-1", "h-hello", "h-hello-2"}},
+	}
+	for _, test := range tests {
+		_, headings := RenderMarkdownWithToc(test.source)
+		got := []string{}
+		for _, heading := range headings {
+			got = append(got, heading.Id)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("RenderMarkdownWithToc(%q) ids = %v, want %v", test.source, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"net/http"
+	"strings"
+)
+
+// The kinds of DiffLine.
+const (
+	DiffSame    = ' '
+	DiffRemoved = '-'
+	DiffAdded   = '+'
+)
+
+// DiffLine is a line of a line-by-line diff.
+type DiffLine struct {
+	Op   byte
+	Text string
+}
+
+func (l DiffLine) Kind() string {
+	switch l.Op {
+	case DiffRemoved:
+		return "removed"
+	case DiffAdded:
+		return "added"
+	}
+	return "same"
+}
+
+// splitLines splits a Source into lines, whatever its line endings.
+func splitLines(s string) []string {
+	return strings.Split(strings.Replace(s, "\r\n", "\n", -1), "\n")
+}
+
+// DiffLines returns the shortest line diff turning a into b.
+func DiffLines(a, b string) []DiffLine {
+	return diffLines(splitLines(a), splitLines(b))
+}
+
+func diffLines(x, y []string) []DiffLine {
+	// lcs[i][j] is the length of the longest common subsequence of x[i:]
+	// and y[j:].
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	diff := []DiffLine{}
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			diff = append(diff, DiffLine{DiffSame, x[i]})
+			i++
+			j++
+		case j == len(y) || (i < len(x) && lcs[i+1][j] >= lcs[i][j+1]):
+			diff = append(diff, DiffLine{DiffRemoved, x[i]})
+			i++
+		default:
+			diff = append(diff, DiffLine{DiffAdded, y[j]})
+			j++
+		}
+	}
+	return diff
+}
+
+// matchLines returns, for every line of x, the index of the line of y it
+// is kept as, or -1 if it was removed.
+func matchLines(x, y []string) []int {
+	match := make([]int, len(x))
+	i, j := 0, 0
+	for _, line := range diffLines(x, y) {
+		switch line.Op {
+		case DiffSame:
+			match[i] = j
+			i++
+			j++
+		case DiffRemoved:
+			match[i] = -1
+			i++
+		case DiffAdded:
+			j++
+		}
+	}
+	return match
+}
+
+// Conflict markers around the two sides of a hunk MergeSources could not
+// resolve.
+const (
+	conflictStart = "<<<<<<< saved version"
+	conflictSplit = "======="
+	conflictEnd   = ">>>>>>> your version"
+)
+
+func conflict(out []string, theirs, mine []string) []string {
+	out = append(out, conflictStart)
+	out = append(out, theirs...)
+	out = append(out, conflictSplit)
+	out = append(out, mine...)
+	return append(out, conflictEnd)
+}
+
+func sameLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// MergeSources combines the Source someone else saved (theirs) with the
+// editor's (mine), both edited from base. A change made on one side only
+// is taken as is, and where both sides changed the same lines differently
+// both are kept between conflict markers for the editor to resolve.
+func MergeSources(base, theirs, mine string) string {
+	o, a, b := splitLines(base), splitLines(theirs), splitLines(mine)
+	matchA, matchB := matchLines(o, a), matchLines(o, b)
+
+	out := []string{}
+	i, ia, ib := 0, 0, 0
+	for {
+		// Copy the lines all three still agree on.
+		for i < len(o) && matchA[i] == ia && matchB[i] == ib {
+			out = append(out, o[i])
+			i, ia, ib = i+1, ia+1, ib+1
+		}
+		if i == len(o) && ia == len(a) && ib == len(b) {
+			break
+		}
+
+		// The hunk runs up to the next base line both sides kept.
+		end, endA, endB := i, len(a), len(b)
+		for end < len(o) && (matchA[end] < 0 || matchB[end] < 0) {
+			end++
+		}
+		if end < len(o) {
+			endA, endB = matchA[end], matchB[end]
+		}
+		old, saved, edited := o[i:end], a[ia:endA], b[ib:endB]
+		switch {
+		case sameLines(saved, old) || sameLines(saved, edited):
+			out = append(out, edited...)
+		case sameLines(edited, old):
+			out = append(out, saved...)
+		default:
+			out = conflict(out, saved, edited)
+		}
+		i, ia, ib = end, endA, endB
+	}
+	return strings.Join(out, "\n")
+}
+
+// MarkDifferences combines two versions of a Source when the one they
+// were both edited from is not known. Lines they share are kept once, and
+// every other line is kept between conflict markers, since there is no
+// telling which side added or removed it.
+func MarkDifferences(theirs, mine string) string {
+	out := []string{}
+	removed, added := []string{}, []string{}
+	flush := func() {
+		if len(removed) > 0 || len(added) > 0 {
+			out = conflict(out, removed, added)
+		}
+		removed, added = removed[:0], added[:0]
+	}
+	for _, line := range DiffLines(theirs, mine) {
+		switch line.Op {
+		case DiffRemoved:
+			removed = append(removed, line.Text)
+		case DiffAdded:
+			added = append(added, line.Text)
+		default:
+			flush()
+			out = append(out, line.Text)
+		}
+	}
+	flush()
+	return strings.Join(out, "\n")
+}
+
+// EditBase is the text of a post as the editor loaded it. It is posted
+// back with the edit, so the edit can be merged with a version someone
+// else saved in the meantime.
+type EditBase struct {
+	Title   string
+	Source  string
+	Excerpt string
+}
+
+func (post BlogPost) EditBase() EditBase {
+	return EditBase{Title: post.Title, Source: string(post.Source), Excerpt: post.Excerpt}
+}
+
+// ReadEditBase reads the base posted with an edit. It reports false for
+// forms from before the base was posted.
+func ReadEditBase(req *http.Request) (EditBase, bool) {
+	base := EditBase{
+		Title:   req.FormValue("base-title"),
+		Source:  req.FormValue("base"),
+		Excerpt: req.FormValue("base-excerpt"),
+	}
+	for _, field := range []string{"base-title", "base", "base-excerpt"} {
+		if _, ok := req.Form[field]; !ok {
+			return base, false
+		}
+	}
+	return base, true
+}
+
+// Merge combines the text of the post someone else saved (theirs) with
+// the editor's (mine). Fields the editor left as they were take the saved
+// value, and the Source is merged line by line.
+func (base EditBase) Merge(theirs, mine BlogPost) EditBase {
+	return EditBase{
+		Title:   mergeField(base.Title, theirs.Title, mine.Title),
+		Source:  MergeSources(base.Source, string(theirs.Source), string(mine.Source)),
+		Excerpt: mergeField(base.Excerpt, theirs.Excerpt, mine.Excerpt),
+	}
+}
+
+func mergeField(base, theirs, mine string) string {
+	if mine == base {
+		return theirs
+	}
+	return mine
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func lines(s ...string) string {
+	return strings.Join(s, "\n")
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want []DiffLine
+	}{
+		{"a", "a", []DiffLine{{DiffSame, "a"}}},
+		{lines("a", "b"), lines("a", "c"), []DiffLine{{DiffSame, "a"}, {DiffRemoved, "b"}, {DiffAdded, "c"}}},
+		{lines("a", "c"), lines("a", "b", "c"), []DiffLine{{DiffSame, "a"}, {DiffAdded, "b"}, {DiffSame, "c"}}},
+		{lines("a", "b", "c"), lines("a", "c"), []DiffLine{{DiffSame, "a"}, {DiffRemoved, "b"}, {DiffSame, "c"}}},
+		{"a\r\nb", lines("a", "b"), []DiffLine{{DiffSame, "a"}, {DiffSame, "b"}}},
+	}
+	for _, test := range tests {
+		if got := DiffLines(test.a, test.b); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("DiffLines(%q, %q) = %v, want %v", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestMergeSources(t *testing.T) {
+	tests := []struct {
+		name               string
+		base, theirs, mine string
+		want               string
+	}{
+		{
+			name:   "unchanged",
+			base:   lines("a", "b"),
+			theirs: lines("a", "b"),
+			mine:   lines("a", "b"),
+			want:   lines("a", "b"),
+		},
+		{
+			name:   "only mine edited",
+			base:   lines("a", "b", "c"),
+			theirs: lines("a", "b", "c"),
+			mine:   lines("a", "B", "c"),
+			want:   lines("a", "B", "c"),
+		},
+		{
+			name:   "only theirs edited",
+			base:   lines("a", "b", "c"),
+			theirs: lines("a", "B", "c"),
+			mine:   lines("a", "b", "c"),
+			want:   lines("a", "B", "c"),
+		},
+		{
+			name:   "they deleted a paragraph",
+			base:   lines("a", "", "b", "", "c"),
+			theirs: lines("a", "", "c"),
+			mine:   lines("a", "", "b", "", "c", "", "d"),
+			want:   lines("a", "", "c", "", "d"),
+		},
+		{
+			name:   "I deleted a paragraph",
+			base:   lines("a", "", "b", "", "c"),
+			theirs: lines("z", "", "a", "", "b", "", "c"),
+			mine:   lines("a", "", "c"),
+			want:   lines("z", "", "a", "", "c"),
+		},
+		{
+			name:   "both added the same line",
+			base:   lines("a", "c"),
+			theirs: lines("a", "b", "c"),
+			mine:   lines("a", "b", "c"),
+			want:   lines("a", "b", "c"),
+		},
+		{
+			name:   "separate edits",
+			base:   lines("a", "b", "c", "d", "e"),
+			theirs: lines("A", "b", "c", "d", "e"),
+			mine:   lines("a", "b", "c", "d", "E"),
+			want:   lines("A", "b", "c", "d", "E"),
+		},
+		{
+			name:   "same line edited differently",
+			base:   lines("a", "b", "c"),
+			theirs: lines("a", "x", "c"),
+			mine:   lines("a", "y", "c"),
+			want:   lines("a", conflictStart, "x", conflictSplit, "y", conflictEnd, "c"),
+		},
+		{
+			name:   "both appended",
+			base:   lines("a"),
+			theirs: lines("a", "x"),
+			mine:   lines("a", "y"),
+			want:   lines("a", conflictStart, "x", conflictSplit, "y", conflictEnd),
+		},
+		{
+			name:   "edited a line they deleted",
+			base:   lines("a", "b", "c"),
+			theirs: lines("a", "c"),
+			mine:   lines("a", "B", "c"),
+			want:   lines("a", conflictStart, conflictSplit, "B", conflictEnd, "c"),
+		},
+	}
+	for _, test := range tests {
+		if got := MergeSources(test.base, test.theirs, test.mine); got != test.want {
+			t.Errorf("%s: MergeSources = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestMarkDifferences(t *testing.T) {
+	tests := []struct {
+		theirs, mine string
+		want         string
+	}{
+		{lines("a", "b"), lines("a", "b"), lines("a", "b")},
+		{lines("a", "c"), lines("a", "b", "c"), lines("a", conflictStart, conflictSplit, "b", conflictEnd, "c")},
+		{lines("a", "b", "c"), lines("a", "c"), lines("a", conflictStart, "b", conflictSplit, conflictEnd, "c")},
+		{lines("a", "b"), lines("a", "c"), lines("a", conflictStart, "b", conflictSplit, "c", conflictEnd)},
+	}
+	for _, test := range tests {
+		if got := MarkDifferences(test.theirs, test.mine); got != test.want {
+			t.Errorf("MarkDifferences(%q, %q) = %q, want %q", test.theirs, test.mine, got, test.want)
+		}
+	}
+}
+
+func TestEditBaseMerge(t *testing.T) {
+	base := EditBase{Title: "Title", Source: lines("a", "b"), Excerpt: "Summary"}
+	tests := []struct {
+		name         string
+		theirs, mine BlogPost
+		want         EditBase
+	}{
+		{
+			name:   "they changed the title and summary",
+			theirs: BlogPost{Title: "Their title", Source: "a\nb", Excerpt: "Their summary"},
+			mine:   BlogPost{Title: "Title", Source: "a\nB", Excerpt: "Summary"},
+			want:   EditBase{Title: "Their title", Source: lines("a", "B"), Excerpt: "Their summary"},
+		},
+		{
+			name:   "I changed the title and summary",
+			theirs: BlogPost{Title: "Title", Source: "A\nb", Excerpt: "Summary"},
+			mine:   BlogPost{Title: "My title", Source: "a\nb", Excerpt: "My summary"},
+			want:   EditBase{Title: "My title", Source: lines("A", "b"), Excerpt: "My summary"},
+		},
+		{
+			name:   "we both changed the title",
+			theirs: BlogPost{Title: "Their title", Source: "a\nb", Excerpt: "Summary"},
+			mine:   BlogPost{Title: "My title", Source: "a\nb", Excerpt: ""},
+			want:   EditBase{Title: "My title", Source: lines("a", "b"), Excerpt: ""},
+		},
+	}
+	for _, test := range tests {
+		if got := base.Merge(test.theirs, test.mine); got != test.want {
+			t.Errorf("%s: Merge = %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"html"
+	"html/template"
+	"strings"
+	"time"
+)
+
+// PageMeta describes a page to search engines and to the sites it is
+// shared on. Every page renders its Open Graph and Twitter meta tags and
+// its schema.org JSON-LD from one of these.
+type PageMeta struct {
+	Title       string
+	Description string
+	Path        string
+	Type        string
+	Image       string
+	NoIndex     bool
+	Embeddable  bool
+	Published   time.Time
+	Modified    time.Time
+	Author      *User
+	Tags        []string
+	LinkedData  []map[string]interface{}
+}
+
+var metaTemplate = template.Must(template.New("meta").Funcs(template.FuncMap{"f8601": f8601, "absolute": absoluteUrl, "oembed": OEmbedUrl}).Parse(`
+{{- if .NoIndex }}
+<meta name="robots" content="noindex, nofollow"/>
+{{- end }}
+{{- with .Description }}
+<meta name="description" content="{{ . }}"/>
+{{- end }}
+<meta property="og:site_name" content="{{ .SiteTitle }}"/>
+<meta property="og:title" content="{{ .Title }} | {{ .Domain }}"/>
+<meta property="og:type" content="{{ .Type }}"/>
+<meta property="og:url" content="{{ .Url }}"/>
+<meta property="og:image" content="{{ .Image }}"/>
+<meta property="og:image:width" content="{{ .ImageWidth }}"/>
+<meta property="og:image:height" content="{{ .ImageHeight }}"/>
+{{- with .Description }}
+<meta property="og:description" content="{{ . }}"/>
+{{- end }}
+{{- if eq .Type "article" }}
+<meta property="article:published_time" content="{{ .Published | f8601 }}"/>
+{{- if not .Modified.IsZero }}
+<meta property="article:modified_time" content="{{ .Modified | f8601 }}"/>
+{{- end }}
+{{- with .Author }}
+<meta property="article:author" content="{{ .ProfileUrl | absolute }}"/>
+{{- end }}
+<meta property="article:publisher" content="{{ .SiteUrl }}"/>
+{{- range .Tags }}
+<meta property="article:tag" content="{{ . }}"/>
+{{- end }}
+{{- end }}
+{{- if eq .Type "profile" }}{{ with .Author }}
+<meta property="profile:username" content="{{ .Username }}"/>
+{{- end }}{{ end }}
+<meta name="twitter:card" content="summary_large_image"/>
+{{- with .Twitter }}
+<meta name="twitter:site" content="{{ . }}"/>
+{{- end }}
+<meta name="twitter:title" content="{{ .Title }}"/>
+<meta name="twitter:url" content="{{ .Url }}"/>
+<meta name="twitter:image" content="{{ .Image }}"/>
+{{- with .Description }}
+<meta name="twitter:description" content="{{ . }}"/>
+{{- end }}
+{{- if .Embeddable }}
+<link rel="alternate" type="application/json+oembed" href="{{ oembed .Url "json" }}" title="{{ .Title }}"/>
+<link rel="alternate" type="text/xml+oembed" href="{{ oembed .Url "xml" }}" title="{{ .Title }}"/>
+{{- end }}
+{{- with .Graph }}
+<script type="application/ld+json">{{ . }}</script>
+{{- end }}
+`))
+
+// Head renders the meta tags and JSON-LD of the page.
+func (m PageMeta) Head() (template.HTML, error) {
+	var buf bytes.Buffer
+	err := metaTemplate.Execute(&buf, map[string]interface{}{
+		"NoIndex":     m.NoIndex,
+		"Embeddable":  m.Embeddable,
+		"Description": m.Description,
+		"SiteTitle":   config.Site.Title,
+		"Domain":      config.Site.Domain,
+		"SiteUrl":     absoluteUrl("/"),
+		"Title":       m.Title,
+		"Type":        m.Type,
+		"Url":         m.Url(),
+		"Image":       m.Image,
+		"ImageWidth":  SocialCardWidth,
+		"ImageHeight": SocialCardHeight,
+		"Published":   m.Published,
+		"Modified":    m.Modified,
+		"Author":      m.Author,
+		"Tags":        m.Tags,
+		"Twitter":     config.Site.Twitter,
+		"Graph":       m.Graph(),
+	})
+	return template.HTML(buf.String()), err
+}
+
+// Url is the absolute URL of the page.
+func (m PageMeta) Url() string {
+	return absoluteUrl(m.Path)
+}
+
+// Graph is the JSON-LD document of the page, or nil if it has none.
+func (m PageMeta) Graph() map[string]interface{} {
+	if len(m.LinkedData) == 0 {
+		return nil
+	}
+	return map[string]interface{}{
+		"@context": "https://schema.org",
+		"@graph":   m.LinkedData,
+	}
+}
+
+// absoluteUrl makes a path on this site into a URL other sites can link
+// to, and leaves URLs alone.
+func absoluteUrl(path string) string {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	return "https://" + config.Site.Domain + path
+}
+
+// SiteMeta describes a page of the site that is not about a post or a
+// person.
+func SiteMeta(title string, path string, description string) PageMeta {
+	if description == "" {
+		description = config.Site.Description
+	}
+	return PageMeta{
+		Title:       title,
+		Description: description,
+		Path:        path,
+		Type:        "website",
+		Image:       SiteCardUrl(),
+		LinkedData:  []map[string]interface{}{WebSiteData()},
+	}
+}
+
+// PostMeta describes a post. Posts that are not public are kept out of
+// search engines, and only published posts can be embedded.
+func PostMeta(post BlogPost, noindex bool) PageMeta {
+	author := post.GetAuthorAsUser()
+	meta := PageMeta{
+		Title:       post.Title,
+		Description: html.UnescapeString(string(post.Summary())),
+		Path:        post.SlugUrl(),
+		Type:        "article",
+		Image:       post.CardImageUrl(),
+		NoIndex:     noindex || post.Unlisted || !post.Published,
+		Embeddable:  !noindex && post.Published,
+		Published:   post.Date,
+		Author:      &author,
+		Tags:        post.Tags,
+	}
+	if post.Edited {
+		meta.Modified = post.DateEdited
+	}
+	meta.LinkedData = []map[string]interface{}{BlogPostingData(post, author, meta), WebSiteData()}
+	return meta
+}
+
+// AuthorMeta describes the profile page of an author.
+func AuthorMeta(author User) PageMeta {
+	return PageMeta{
+		Title:      author.DisplayName,
+		Path:       author.ProfileUrl(),
+		Type:       "profile",
+		Image:      SiteCardUrl(),
+		Author:     &author,
+		LinkedData: []map[string]interface{}{PersonData(author), WebSiteData()},
+	}
+}
+
+// WebSiteData is the schema.org WebSite of the site.
+func WebSiteData() map[string]interface{} {
+	return map[string]interface{}{
+		"@type":       "WebSite",
+		"@id":         absoluteUrl("/#website"),
+		"url":         absoluteUrl("/"),
+		"name":        config.Site.Title,
+		"description": config.Site.Description,
+	}
+}
+
+// PersonData is the schema.org Person of a user.
+func PersonData(user User) map[string]interface{} {
+	return map[string]interface{}{
+		"@type": "Person",
+		"@id":   absoluteUrl(user.ProfileUrl() + "#person"),
+		"url":   absoluteUrl(user.ProfileUrl()),
+		"name":  user.DisplayName,
+		"image": absoluteUrl(user.Avatar()),
+	}
+}
+
+// BlogPostingData is the schema.org BlogPosting of a post.
+func BlogPostingData(post BlogPost, author User, meta PageMeta) map[string]interface{} {
+	person := PersonData(author)
+	data := map[string]interface{}{
+		"@type":            "BlogPosting",
+		"@id":              meta.Url() + "#post",
+		"url":              meta.Url(),
+		"mainEntityOfPage": meta.Url(),
+		"headline":         post.Title,
+		"description":      meta.Description,
+		"image":            []string{meta.Image, post.HeaderImageUrl()},
+		"datePublished":    f8601(post.Date),
+		"author":           person,
+		"publisher":        map[string]interface{}{"@id": person["@id"]},
+		"isPartOf":         map[string]interface{}{"@id": absoluteUrl("/#website")},
+		"wordCount":        post.WordCount,
+	}
+	if !meta.Modified.IsZero() {
+		data["dateModified"] = f8601(meta.Modified)
+	}
+	if len(post.Tags) > 0 {
+		data["keywords"] = strings.Join(post.Tags, ", ")
+	}
+	return data
+}
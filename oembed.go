@@ -0,0 +1,174 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"html/template"
+	"image"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Rich embeds are drawn at EmbedWidth×EmbedHeight, shrunk to fit the size
+// the consumer asks for. Below the minimum size a plain link is returned.
+const (
+	EmbedWidth     = 600
+	EmbedHeight    = 240
+	EmbedMinWidth  = 280
+	EmbedMinHeight = 120
+	EmbedCacheAge  = 3600
+)
+
+// OEmbed is an oEmbed response, see https://oembed.com.
+type OEmbed struct {
+	XMLName         xml.Name `json:"-" xml:"oembed"`
+	Version         string   `json:"version" xml:"version"`
+	Type            string   `json:"type" xml:"type"`
+	Title           string   `json:"title" xml:"title"`
+	AuthorName      string   `json:"author_name,omitempty" xml:"author_name,omitempty"`
+	AuthorUrl       string   `json:"author_url,omitempty" xml:"author_url,omitempty"`
+	ProviderName    string   `json:"provider_name" xml:"provider_name"`
+	ProviderUrl     string   `json:"provider_url" xml:"provider_url"`
+	CacheAge        int      `json:"cache_age" xml:"cache_age"`
+	ThumbnailUrl    string   `json:"thumbnail_url,omitempty" xml:"thumbnail_url,omitempty"`
+	ThumbnailWidth  int      `json:"thumbnail_width,omitempty" xml:"thumbnail_width,omitempty"`
+	ThumbnailHeight int      `json:"thumbnail_height,omitempty" xml:"thumbnail_height,omitempty"`
+	Html            string   `json:"html,omitempty" xml:"html,omitempty"`
+	Width           int      `json:"width,omitempty" xml:"width,omitempty"`
+	Height          int      `json:"height,omitempty" xml:"height,omitempty"`
+}
+
+var embedTemplate = template.Must(template.New("embed").Parse(
+	`<blockquote class="post-embed" cite="{{ .url }}" style="max-width:{{ .width }}px;">` +
+		`<p><a href="{{ .url }}"><strong>{{ .post.Title }}</strong></a></p>` +
+		`<p>{{ .post.Summary }}</p>` +
+		`&mdash; <a href="{{ .authorUrl }}">{{ .author.DisplayName }}</a>, {{ .post.Date.Format "January 2, 2006" }}` +
+		`</blockquote>`))
+
+// OEmbedUrl is the oEmbed endpoint describing the page at u in format.
+func OEmbedUrl(u string, format string) string {
+	return absoluteUrl(reverse("oembed")) + "?" + url.Values{"url": {u}, "format": {format}}.Encode()
+}
+
+// EmbeddedPost returns the published post at u, a URL of this site in
+// either the slug or the id form.
+func EmbeddedPost(u string) (BlogPost, bool) {
+	parsed, err := url.Parse(u)
+	if err != nil || (parsed.Host != config.Site.Domain && parsed.Host != "www."+config.Site.Domain) {
+		return BlogPost{}, false
+	}
+	id, ok := PostForPath(strings.TrimSuffix(parsed.Path, "/"))
+	if !ok {
+		return BlogPost{}, false
+	}
+	post, err := GetBlogPostWithId(id)
+	return post, err == nil
+}
+
+// PostOEmbed describes the post as a rich embed no larger than maxWidth by
+// maxHeight, or as a link if a rich embed can't fit. Zero means no limit.
+func PostOEmbed(post BlogPost, maxWidth int, maxHeight int) (OEmbed, error) {
+	author := post.GetAuthorAsUser()
+	embed := OEmbed{
+		Version:      "1.0",
+		Type:         "link",
+		Title:        post.Title,
+		AuthorName:   author.DisplayName,
+		AuthorUrl:    absoluteUrl(author.ProfileUrl()),
+		ProviderName: config.Site.Title,
+		ProviderUrl:  absoluteUrl("/"),
+		CacheAge:     EmbedCacheAge,
+	}
+
+	thumb, width, height := postThumbnail(post)
+	if fits(width, maxWidth) && fits(height, maxHeight) {
+		embed.ThumbnailUrl, embed.ThumbnailWidth, embed.ThumbnailHeight = thumb, width, height
+	}
+
+	width, height = EmbedWidth, EmbedHeight
+	if maxWidth > 0 && maxWidth < width {
+		width = maxWidth
+	}
+	if maxHeight > 0 && maxHeight < height {
+		height = maxHeight
+	}
+	if width < EmbedMinWidth || height < EmbedMinHeight {
+		return embed, nil
+	}
+
+	var buf bytes.Buffer
+	err := embedTemplate.Execute(&buf, map[string]interface{}{
+		"url":       absoluteUrl(post.SlugUrl()),
+		"post":      post,
+		"author":    author,
+		"authorUrl": embed.AuthorUrl,
+		"width":     width,
+	})
+	if err != nil {
+		return embed, err
+	}
+	embed.Type = "rich"
+	embed.Html = buf.String()
+	embed.Width, embed.Height = width, height
+	return embed, nil
+}
+
+func fits(size int, max int) bool {
+	return max <= 0 || size <= max
+}
+
+// postThumbnail returns the header image of the post and its size, or the
+// social card of posts without one.
+func postThumbnail(post BlogPost) (string, int, int) {
+	if post.HasImage() {
+		src := post.HeaderImage()
+		if in, err := os.Open("./static/" + strings.TrimPrefix(src, "/assets/")); err == nil {
+			defer in.Close()
+			if conf, _, err := image.DecodeConfig(in); err == nil {
+				return absoluteUrl(src), conf.Width, conf.Height
+			}
+		}
+	}
+	return post.CardImageUrl(), SocialCardWidth, SocialCardHeight
+}
+
+// OEmbedHandler answers oEmbed requests for the posts of the site.
+func OEmbedHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) error {
+	format := req.FormValue("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "xml" {
+		w.WriteHeader(http.StatusNotImplemented)
+		return nil
+	}
+	post, ok := EmbeddedPost(req.FormValue("url"))
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return nil
+	}
+	maxWidth, _ := strconv.Atoi(req.FormValue("maxwidth"))
+	maxHeight, _ := strconv.Atoi(req.FormValue("maxheight"))
+	embed, err := PostOEmbed(post, maxWidth, maxHeight)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(EmbedCacheAge))
+	if format == "json" {
+		return writeJson(w, http.StatusOK, embed)
+	}
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write([]byte(xml.Header)); err != nil {
+		return err
+	}
+	return xml.NewEncoder(w).Encode(embed)
+}
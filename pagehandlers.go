@@ -4,29 +4,114 @@
 package main
 
 import (
+	"github.com/gorilla/mux"
+	"image/png"
+	"io/ioutil"
 	"net/http"
 )
 
+const AuthorPostsPerPage = 12
+
 func StickerPageHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
 	http.Redirect(w, req, "https://henry.computer", http.StatusFound)
 	return nil
 }
 
 func IndexPageHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
+	pinned := GetPinnedBlogs()
+	featured := GetFeaturedBlogs(FeaturedCarouselSize, blogIds(pinned))
 	return T("pages/index.html", pjax).Execute(w, map[string]interface{}{
-		"ctx":   ctx,
-		"blogs": GetBlogs(6, 0),
+		"ctx":      ctx,
+		"meta":     SiteMeta("Index", reverse("index"), ""),
+		"pinned":   pinned,
+		"featured": featured,
+		"rows":     LayoutCards(GetBlogsExcept(6, blogIds(pinned, featured))),
+		"popular":  GetPopularBlogs(PopularCount),
+		"trending": GetTrendingBlogs(TrendingCount),
 	})
 }
 
 func BioPageHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
 	return T("pages/bio.html", pjax).Execute(w, map[string]interface{}{
-		"ctx": ctx,
+		"ctx":  ctx,
+		"meta": SiteMeta("Henry's Bio", reverse("bio"), "Henry Slawniak's biography"),
 	})
 }
 
 func ClockPageHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
 	return T("pages/clock.html", pjax).Execute(w, map[string]interface{}{
-		"ctx": ctx,
+		"ctx":  ctx,
+		"meta": SiteMeta("World Clock", reverse("clock"), ""),
+	})
+}
+
+func CodeThemeHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
+	theme := mux.Vars(req)["theme"]
+	if !CodeThemeExists(theme) {
+		return NotFoundHandler(w, req, ctx, pjax)
+	}
+	w.Header().Set("Content-Type", "text/css; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	return WriteCodeThemeCSS(w, theme)
+}
+
+func AuthorPageHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
+	author, err := GetUserByName(mux.Vars(req)["username"])
+	if err != nil || (!author.IsBlogAuthor && !author.IsAdmin) {
+		return NotFoundHandler(w, req, ctx, pjax)
+	}
+
+	page := pageFromRequest(req)
+	blogs, err := GetBlogsByAuthor(author, AuthorPostsPerPage, page)
+	if err != nil {
+		return err
+	}
+	if len(blogs) == 0 && page > 1 {
+		return NotFoundHandler(w, req, ctx, pjax)
+	}
+
+	return T("pages/author.html", pjax).Execute(w, map[string]interface{}{
+		"ctx":    ctx,
+		"author": author,
+		"meta":   AuthorMeta(*author),
+		"blogs":  blogs,
+		"page":   page,
+		"prev":   page - 1,
+		"next":   page + 1,
+		"more":   CountBlogsByAuthor(author) > page*AuthorPostsPerPage,
 	})
 }
+
+func IdenticonHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
+	img, err := GenerateIdenticon(mux.Vars(req)["hash"])
+	if err != nil {
+		return NotFoundHandler(w, req, ctx, pjax)
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "public, max-age=604800")
+	return png.Encode(w, img)
+}
+
+// SocialCardHandler serves the social card of a post, or of the site.
+func SocialCardHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
+	name := mux.Vars(req)["name"]
+	var file string
+	if name == "site" {
+		file, err = SiteCardImage()
+	} else if post, ok := cardPost(name); ok {
+		file, err = PostCardImage(post)
+	} else {
+		return NotFoundHandler(w, req, ctx, pjax)
+	}
+	if err != nil {
+		return err
+	}
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "public, max-age=604800")
+	_, err = w.Write(content)
+	return err
+}
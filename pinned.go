@@ -0,0 +1,94 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"gopkg.in/mgo.v2/bson"
+	"time"
+)
+
+// FeaturedCarouselSize is how many featured posts the home page shows.
+const FeaturedCarouselSize = 5
+
+// IsPinned is whether the post is pinned right now.
+func (post BlogPost) IsPinned() bool {
+	return post.Pinned && (post.PinExpires.IsZero() || post.PinExpires.After(time.Now()))
+}
+
+// Pin pins the post to the top of the home page and blog index, in order
+// of order, until expires. A zero expires keeps it pinned.
+func (post *BlogPost) Pin(order int, expires time.Time) error {
+	post.Pinned = true
+	post.PinOrder = order
+	post.PinExpires = expires.UTC()
+	set := bson.M{"pinned": true, "pinorder": order}
+	update := bson.M{"$set": set}
+	if expires.IsZero() {
+		update["$unset"] = bson.M{"pinexpires": ""}
+	} else {
+		set["pinexpires"] = post.PinExpires
+	}
+	return post.update(nil, update)
+}
+
+func (post *BlogPost) Unpin() error {
+	post.Pinned = false
+	post.PinOrder = 0
+	post.PinExpires = time.Time{}
+	return post.update(nil, bson.M{
+		"$unset": bson.M{"pinned": "", "pinorder": "", "pinexpires": ""},
+	})
+}
+
+// GetPinnedBlogs returns the posts pinned right now, in pin order.
+func GetPinnedBlogs() []BlogPost {
+	localsession := session.Copy()
+	defer localsession.Close()
+	blogs := []BlogPost{}
+	localsession.DB(database).C("blogs").Find(listed(bson.M{
+		"published": true,
+		"pinned":    true,
+		"$or": []bson.M{
+			{"pinexpires": bson.M{"$exists": false}},
+			{"pinexpires": bson.M{"$gt": time.Now().UTC()}},
+		},
+	})).Sort("pinorder", "-date").All(&blogs)
+	return blogs
+}
+
+// GetFeaturedBlogs returns the newest featured posts, except the given ones.
+func GetFeaturedBlogs(count int, except []bson.ObjectId) []BlogPost {
+	localsession := session.Copy()
+	defer localsession.Close()
+	blogs := []BlogPost{}
+	localsession.DB(database).C("blogs").Find(listed(bson.M{
+		"published": true,
+		"featured":  true,
+		"_id":       bson.M{"$nin": except},
+	})).Sort("-date").Limit(count).All(&blogs)
+	return blogs
+}
+
+// GetBlogsExcept returns the newest posts, leaving out the given ones
+// because they are already on the page.
+func GetBlogsExcept(count int, except []bson.ObjectId) []BlogPost {
+	localsession := session.Copy()
+	defer localsession.Close()
+	blogs := []BlogPost{}
+	localsession.DB(database).C("blogs").Find(listed(bson.M{
+		"published": true,
+		"_id":       bson.M{"$nin": except},
+	})).Sort("-date").Limit(count).All(&blogs)
+	return blogs
+}
+
+func blogIds(posts ...[]BlogPost) []bson.ObjectId {
+	ids := []bson.ObjectId{}
+	for _, list := range posts {
+		for _, post := range list {
+			ids = append(ids, post.Id)
+		}
+	}
+	return ids
+}
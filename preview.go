@@ -0,0 +1,76 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"gopkg.in/mgo.v2/bson"
+	"strings"
+	"time"
+)
+
+// The lifetime of preview tokens, in days.
+const (
+	PreviewDefaultDays = 7
+	PreviewMaxDays     = 30
+)
+
+var (
+	ErrPreviewInvalid = errors.New("this preview link is not valid")
+	ErrPreviewExpired = errors.New("this preview link has expired")
+)
+
+var previewEncoding = base64.RawURLEncoding
+
+func previewSignature(payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte("preview:"+config.Server.Secret))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// PreviewToken returns a token granting read access to the post until
+// expires, without an account. It is signed with the server secret, so
+// changing the secret revokes every token.
+func (post BlogPost) PreviewToken(expires time.Time) string {
+	payload := make([]byte, 12+8)
+	copy(payload, post.Id)
+	binary.BigEndian.PutUint64(payload[12:], uint64(expires.Unix()))
+	return previewEncoding.EncodeToString(payload) + "." + previewEncoding.EncodeToString(previewSignature(payload))
+}
+
+func (post BlogPost) PreviewUrl(expires time.Time) string {
+	return strings.Join([]string{reverse("blog-preview"), post.PreviewToken(expires)}, "/")
+}
+
+// ParsePreviewToken checks a token made by PreviewToken and returns the id
+// of the post it is for.
+func ParsePreviewToken(token string) (bson.ObjectId, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return "", ErrPreviewInvalid
+	}
+	payload, err := previewEncoding.DecodeString(parts[0])
+	if err != nil || len(payload) != 12+8 {
+		return "", ErrPreviewInvalid
+	}
+	signature, err := previewEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(signature, previewSignature(payload)) {
+		return "", ErrPreviewInvalid
+	}
+	if time.Now().Unix() > int64(binary.BigEndian.Uint64(payload[12:])) {
+		return "", ErrPreviewExpired
+	}
+	return bson.ObjectId(payload[:12]), nil
+}
+
+// listed restricts a query to posts that show up in listings. Unlisted
+// posts are only reachable by their URL.
+func listed(query bson.M) bson.M {
+	query["unlisted"] = bson.M{"$ne": true}
+	return live(query)
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"gopkg.in/mgo.v2/bson"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParsePreviewToken(t *testing.T) {
+	defer func(secret string) { config.Server.Secret = secret }(config.Server.Secret)
+	config.Server.Secret = "preview test secret"
+	post := BlogPost{Id: bson.NewObjectId()}
+	valid := post.PreviewToken(time.Now().Add(time.Hour))
+	payload := strings.Split(valid, ".")[0]
+	other := BlogPost{Id: bson.NewObjectId()}.PreviewToken(time.Now().Add(time.Hour))
+
+	tests := []struct {
+		name  string
+		token string
+		err   error
+	}{
+		{"valid", valid, nil},
+		{"expired", post.PreviewToken(time.Now().Add(-time.Minute)), ErrPreviewExpired},
+		{"empty", "", ErrPreviewInvalid},
+		{"no signature", payload, ErrPreviewInvalid},
+		{"bad encoding", payload + ".!!", ErrPreviewInvalid},
+		{"short payload", payload[:8] + "." + strings.Split(valid, ".")[1], ErrPreviewInvalid},
+		{"signature of another post", payload + "." + strings.Split(other, ".")[1], ErrPreviewInvalid},
+		{"extra part", valid + ".x", ErrPreviewInvalid},
+	}
+	for _, test := range tests {
+		id, err := ParsePreviewToken(test.token)
+		if err != test.err {
+			t.Errorf("%s: ParsePreviewToken error = %v, want %v", test.name, err, test.err)
+		} else if err == nil && id != post.Id {
+			t.Errorf("%s: ParsePreviewToken = %v, want %v", test.name, id, post.Id)
+		}
+	}
+
+	config.Server.Secret = "changed secret"
+	if _, err := ParsePreviewToken(valid); err != ErrPreviewInvalid {
+		t.Errorf("changing the secret: ParsePreviewToken error = %v, want %v", err, ErrPreviewInvalid)
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"gopkg.in/mgo.v2/bson"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// RelatedPostCount is how many related posts are stored for every post.
+const RelatedPostCount = 3
+
+// RelatedTagWeight is how much sharing every tag counts for, compared to
+// the Sources being identical.
+const RelatedTagWeight = 0.5
+
+// relatedRefresh holds a pending request to recompute related posts.
+var relatedRefresh = make(chan struct{}, 1)
+
+var stopWords = map[string]bool{}
+
+func init() {
+	for _, word := range strings.Fields(`about above after again against all also and any are because been
+		before being below between both but can could did does doing down during each few for from further
+		had has have having her here hers him his how into its itself just more most much not now off once
+		only other our ours out over own same she should some such than that the their theirs them then there
+		these they this those through too under until very was were what when where which while who whom why
+		will with would you your yours`) {
+		stopWords[word] = true
+	}
+}
+
+// terms splits Markdown source into lower case words worth comparing.
+func terms(source string) []string {
+	words := strings.FieldsFunc(strings.ToLower(source), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	kept := words[:0]
+	for _, word := range words {
+		if len([]rune(word)) > 2 && !stopWords[word] {
+			kept = append(kept, word)
+		}
+	}
+	return kept
+}
+
+// tfidf returns the unit length TF-IDF vector of every document.
+func tfidf(documents [][]string) []map[string]float64 {
+	df := map[string]int{}
+	counts := make([]map[string]int, len(documents))
+	for i, words := range documents {
+		counts[i] = map[string]int{}
+		for _, word := range words {
+			counts[i][word]++
+		}
+		for word := range counts[i] {
+			df[word]++
+		}
+	}
+
+	vectors := make([]map[string]float64, len(documents))
+	for i, words := range documents {
+		vectors[i] = map[string]float64{}
+		norm := 0.0
+		for word, n := range counts[i] {
+			weight := float64(n) / float64(len(words)) * math.Log(float64(len(documents))/float64(df[word]))
+			if weight > 0 {
+				vectors[i][word] = weight
+				norm += weight * weight
+			}
+		}
+		norm = math.Sqrt(norm)
+		for word := range vectors[i] {
+			vectors[i][word] /= norm
+		}
+	}
+	return vectors
+}
+
+func cosine(a, b map[string]float64) float64 {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+	sum := 0.0
+	for word, weight := range a {
+		sum += weight * b[word]
+	}
+	return sum
+}
+
+// tagOverlap is the Jaccard index of two sets of tags, ignoring case.
+func tagOverlap(a, b []string) float64 {
+	set := map[string]bool{}
+	for _, tag := range a {
+		set[strings.ToLower(tag)] = true
+	}
+	shared, union := 0, len(set)
+	for _, tag := range b {
+		tag = strings.ToLower(tag)
+		if set[tag] {
+			shared++
+			set[tag] = false
+		} else if _, seen := set[tag]; !seen {
+			union++
+			set[tag] = false
+		}
+	}
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
+
+// RelatedPosts works out the related posts of every published post from
+// their shared tags and the TF-IDF similarity of their Sources.
+func RelatedPosts(posts []BlogPost) map[bson.ObjectId][]bson.ObjectId {
+	documents := make([][]string, len(posts))
+	for i, post := range posts {
+		documents[i] = terms(string(post.Source))
+	}
+	vectors := tfidf(documents)
+
+	type scored struct {
+		id    bson.ObjectId
+		score float64
+	}
+	related := map[bson.ObjectId][]bson.ObjectId{}
+	for i, post := range posts {
+		candidates := []scored{}
+		for j, other := range posts {
+			if i == j {
+				continue
+			}
+			score := cosine(vectors[i], vectors[j]) + RelatedTagWeight*tagOverlap(post.Tags, other.Tags)
+			if score > 0 {
+				candidates = append(candidates, scored{other.Id, score})
+			}
+		}
+		sort.SliceStable(candidates, func(a, b int) bool {
+			return candidates[a].score > candidates[b].score
+		})
+		ids := []bson.ObjectId{}
+		for k := 0; k < len(candidates) && k < RelatedPostCount; k++ {
+			ids = append(ids, candidates[k].id)
+		}
+		related[post.Id] = ids
+	}
+	return related
+}
+
+// RefreshRelatedPosts recomputes and stores the related posts of every
+// post, since saving one post can change what is related to all the others.
+func RefreshRelatedPosts() error {
+	localsession := session.Copy()
+	defer localsession.Close()
+	blogs := localsession.DB(database).C("blogs")
+
+	posts := []BlogPost{}
+	err := blogs.Find(listed(bson.M{"published": true})).Select(bson.M{"source": 1, "tags": 1, "related": 1}).Sort("-date").All(&posts)
+	if err != nil {
+		return err
+	}
+	related := RelatedPosts(posts)
+	for _, post := range posts {
+		if equalIds(post.Related, related[post.Id]) {
+			continue
+		}
+		if err := blogs.UpdateId(post.Id, bson.M{"$set": bson.M{"related": related[post.Id]}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ScheduleRelatedRefresh asks RelatedPostsLoop to recompute related posts.
+// Requests made while one is pending are merged into it.
+func ScheduleRelatedRefresh() {
+	select {
+	case relatedRefresh <- struct{}{}:
+	default:
+	}
+}
+
+// FlushRelatedRefresh runs a pending refresh right away, for commands that
+// exit before RelatedPostsLoop would get to it.
+func FlushRelatedRefresh() error {
+	select {
+	case <-relatedRefresh:
+		return RefreshRelatedPosts()
+	default:
+		return nil
+	}
+}
+
+func RelatedPostsLoop() {
+	for range relatedRefresh {
+		if err := RefreshRelatedPosts(); err != nil {
+			log.Warning(fmt.Sprintf("related posts: %s", err))
+		}
+	}
+}
+
+// GetRelatedPosts returns the stored related posts that are still
+// published, in order.
+func (post BlogPost) GetRelatedPosts() []BlogPost {
+	if len(post.Related) == 0 {
+		return nil
+	}
+	return blogsInOrder(post.Related, len(post.Related))
+}
+
+// ParseTags splits a comma separated list of tags.
+func ParseTags(s string) []string {
+	tags := []string{}
+	for _, tag := range strings.Split(s, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func equalIds(a, b []bson.ObjectId) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
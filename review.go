@@ -0,0 +1,393 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"gopkg.in/mgo.v2/bson"
+	"html/template"
+	"strings"
+	"time"
+)
+
+// The editorial states of a post. Authors submit drafts for review,
+// reviewers approve them or request changes, and approved posts can be
+// published. Admins can publish from any state.
+const (
+	StateDraft            = "draft"
+	StateInReview         = "review"
+	StateChangesRequested = "changes"
+	StateApproved         = "approved"
+	StatePublished        = "published"
+)
+
+// ReviewQuoteLength is how much of a paragraph is kept with a comment on it.
+const ReviewQuoteLength = 80
+
+var stateNames = map[string]string{
+	StateDraft:            "Draft",
+	StateInReview:         "In review",
+	StateChangesRequested: "Changes requested",
+	StateApproved:         "Approved",
+	StatePublished:        "Published",
+}
+
+// nextStates lists the states each state can move to.
+var nextStates = map[string][]string{
+	StateDraft:            {StateInReview},
+	StateChangesRequested: {StateInReview, StateDraft},
+	StateInReview:         {StateApproved, StateChangesRequested, StateDraft},
+	StateApproved:         {StatePublished, StateDraft},
+	StatePublished:        {StateDraft},
+}
+
+var (
+	ErrTransitionInvalid = errors.New("the post can't move to that state")
+	ErrTransitionDenied  = errors.New("you can't move the post to that state")
+)
+
+// Transition records a change of the state of a post.
+type Transition struct {
+	From string
+	To   string
+	By   bson.ObjectId `bson:"_by"`
+	Date time.Time
+	Note string
+}
+
+// systemUser stands for the site itself in the history of a post, for
+// changes nobody made by hand, like a git sync unpublishing a post whose
+// file was removed.
+var systemUser = User{DisplayName: "The site", IsAdmin: true}
+
+func (t Transition) GetUser() User {
+	if t.By == "" {
+		return systemUser
+	}
+	return getUserById(t.By)
+}
+
+// ReviewComment is a comment on one paragraph of the Source of a post.
+type ReviewComment struct {
+	Id        bson.ObjectId
+	Paragraph int
+	Quote     string
+	Text      string
+	By        bson.ObjectId `bson:"_by"`
+	Date      time.Time
+	Resolved  bool
+}
+
+func (c ReviewComment) GetUser() User {
+	return getUserById(c.By)
+}
+
+func getUserById(id bson.ObjectId) User {
+	localsession := session.Copy()
+	defer localsession.Close()
+	user := User{}
+	localsession.DB(database).C("users").FindId(id).One(&user)
+	return user
+}
+
+func StateName(state string) string {
+	return stateNames[state]
+}
+
+// CanReview is whether the user reviews other authors' posts.
+func (u User) CanReview() bool {
+	return u.IsReviewer || u.IsAdmin
+}
+
+// CurrentState is the editorial state of the post. Posts from before the
+// review workflow are either published or drafts.
+func (post BlogPost) CurrentState() string {
+	if post.State != "" {
+		return post.State
+	}
+	if post.Published {
+		return StatePublished
+	}
+	return StateDraft
+}
+
+func (post BlogPost) ReviewUrl() string {
+	return strings.Join([]string{reverse("blog-review"), post.Id.Hex()}, "/")
+}
+
+// CanView is whether the user can see the post before it is published.
+func (post BlogPost) CanView(user User) bool {
+	return post.CanEdit(user) || user.CanReview()
+}
+
+// CanTransition is whether the user may move the post to the state to.
+// Reviewers can't approve their own posts, and only admins can publish a
+// post that has not been approved.
+func (post BlogPost) CanTransition(to string, user User) bool {
+	from := post.CurrentState()
+	if user.IsAdmin && (to == StatePublished || to == StateDraft) && from != to {
+		return true
+	}
+	allowed := false
+	for _, state := range nextStates[from] {
+		allowed = allowed || state == to
+	}
+	if !allowed {
+		return false
+	}
+	switch to {
+	case StateApproved, StateChangesRequested:
+		return user.CanReview() && (post.Author != user.Id || user.IsAdmin)
+	default:
+		return post.CanEdit(user)
+	}
+}
+
+// NextStates returns the states the user can move the post to.
+func (post BlogPost) NextStates(user User) []string {
+	states := []string{}
+	for _, to := range []string{StateInReview, StateChangesRequested, StateApproved, StatePublished, StateDraft} {
+		if post.CanTransition(to, user) {
+			states = append(states, to)
+		}
+	}
+	return states
+}
+
+// Transition moves the post to the state to and records who did it.
+func (post *BlogPost) Transition(to string, user User, note string) error {
+	if _, ok := stateNames[to]; !ok {
+		return ErrTransitionInvalid
+	}
+	if !post.CanTransition(to, user) {
+		return ErrTransitionDenied
+	}
+
+	t := Transition{
+		From: post.CurrentState(),
+		To:   to,
+		By:   user.Id,
+		Date: time.Now().UTC(),
+		Note: strings.TrimSpace(note),
+	}
+	post.State = to
+	post.Published = to == StatePublished
+	post.Transitions = append(post.Transitions, t)
+
+	err := post.update(nil, bson.M{
+		"$set":  bson.M{"state": post.State, "published": post.Published},
+		"$push": bson.M{"transitions": t},
+	})
+	ScheduleRelatedRefresh()
+	return err
+}
+
+// ReviewEdit sends the post back for review, taking it offline if it was
+// published, when user changed it after approval and can't publish it
+// themselves. Approval only covers the text that was reviewed. It reports
+// whether the post went back for review.
+func (post *BlogPost) ReviewEdit(user User) bool {
+	from := post.CurrentState()
+	if user.IsAdmin || (from != StateApproved && from != StatePublished) {
+		return false
+	}
+	post.moveTo(StateInReview, user.Id, "Edited while "+strings.ToLower(StateName(from)))
+	return true
+}
+
+// ImportState moves an imported post to the state its front matter asks
+// for. edited is whether the import changes the text. Admins publish
+// directly, and anyone else only publishes text that was approved; other
+// posts asked to be published go to review. Unpublishing takes a published
+// post back to a draft, and other review states are kept.
+func (post *BlogPost) ImportState(publish bool, edited bool, author User) {
+	from := post.CurrentState()
+	reviewed := !edited && (from == StatePublished || from == StateApproved)
+	to := from
+	switch {
+	case !publish && from == StatePublished:
+		to = StateDraft
+	case !publish:
+		if from == StateApproved && edited && !author.IsAdmin {
+			to = StateInReview
+		}
+	case author.IsAdmin || reviewed:
+		to = StatePublished
+	default:
+		to = StateInReview
+	}
+	if to != from {
+		post.moveTo(to, author.Id, "Imported")
+	}
+}
+
+// moveTo changes the state of the post in memory and records the
+// transition. The caller stores the post.
+func (post *BlogPost) moveTo(to string, by bson.ObjectId, note string) {
+	post.Transitions = append(post.Transitions, Transition{
+		From: post.CurrentState(),
+		To:   to,
+		By:   by,
+		Date: time.Now().UTC(),
+		Note: note,
+	})
+	post.State = to
+	post.Published = to == StatePublished
+}
+
+// SourceParagraphs splits Markdown source into its blocks, keeping fenced
+// code blocks whole. Review comments refer to these by index.
+func SourceParagraphs(source string) []string {
+	paragraphs := []string{}
+	current := []string{}
+	fence := ""
+	flush := func() {
+		if len(current) > 0 {
+			paragraphs = append(paragraphs, strings.Join(current, "\n"))
+			current = current[:0]
+		}
+	}
+	for _, line := range strings.Split(strings.Replace(source, "\r\n", "\n", -1), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:3]
+		case trimmed == "":
+			flush()
+			continue
+		}
+		current = append(current, line)
+	}
+	flush()
+	return paragraphs
+}
+
+// ReviewParagraph is a paragraph of a post with the comments on it.
+type ReviewParagraph struct {
+	Index    int
+	Source   string
+	Content  template.HTML
+	Comments []ReviewComment
+}
+
+// ReviewParagraphs pairs the paragraphs of the post with their comments.
+// Comments on paragraphs that no longer exist go on the last one.
+func (post BlogPost) ReviewParagraphs() []ReviewParagraph {
+	paragraphs := []ReviewParagraph{}
+	for i, source := range SourceParagraphs(string(post.Source)) {
+		paragraphs = append(paragraphs, ReviewParagraph{Index: i, Source: source, Content: RenderMarkdown(source)})
+	}
+	if len(paragraphs) == 0 {
+		paragraphs = append(paragraphs, ReviewParagraph{})
+	}
+	for _, c := range post.ReviewComments {
+		i := c.Paragraph
+		if i < 0 || i >= len(paragraphs) {
+			i = len(paragraphs) - 1
+		}
+		paragraphs[i].Comments = append(paragraphs[i].Comments, c)
+	}
+	return paragraphs
+}
+
+// OpenComments counts the review comments that are not resolved.
+func (post BlogPost) OpenComments() int {
+	n := 0
+	for _, c := range post.ReviewComments {
+		if !c.Resolved {
+			n++
+		}
+	}
+	return n
+}
+
+// AddReviewComment comments on a paragraph of the Source of the post.
+func (post *BlogPost) AddReviewComment(paragraph int, text string, user User) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return errors.New("the comment is empty")
+	}
+	paragraphs := SourceParagraphs(string(post.Source))
+	if paragraph < 0 || paragraph >= len(paragraphs) {
+		return errors.New("there is no such paragraph")
+	}
+
+	c := ReviewComment{
+		Id:        bson.NewObjectId(),
+		Paragraph: paragraph,
+		Quote:     TruncateWords(strings.Join(strings.Fields(paragraphs[paragraph]), " "), ReviewQuoteLength),
+		Text:      text,
+		By:        user.Id,
+		Date:      time.Now().UTC(),
+	}
+	post.ReviewComments = append(post.ReviewComments, c)
+	return post.update(nil, bson.M{"$push": bson.M{"reviewcomments": c}})
+}
+
+// ResolveReviewComment marks a review comment as dealt with.
+func (post *BlogPost) ResolveReviewComment(id bson.ObjectId) error {
+	for i := range post.ReviewComments {
+		if post.ReviewComments[i].Id == id {
+			post.ReviewComments[i].Resolved = true
+			return post.update(
+				bson.M{"reviewcomments.id": id},
+				bson.M{"$set": bson.M{"reviewcomments.$.resolved": true}},
+			)
+		}
+	}
+	return errors.New("there is no such comment")
+}
+
+// GetReviewQueue returns the unpublished posts the user works on: those
+// waiting for review for reviewers, and their own for authors.
+func GetReviewQueue(user User) []BlogPost {
+	localsession := session.Copy()
+	defer localsession.Close()
+	or := []bson.M{{"_author": user.Id}}
+	if user.CanReview() {
+		or = append(or, bson.M{"state": StateInReview})
+	}
+	if user.IsAdmin {
+		or = append(or, bson.M{"state": StateApproved})
+	}
+	blogs := []BlogPost{}
+	localsession.DB(database).C("blogs").Find(live(bson.M{"published": bson.M{"$ne": true}, "$or": or})).Sort("-date").All(&blogs)
+	return blogs
+}
+
+func init() {
+	RegisterCommand("reviewer", Command{
+		Usage: "[-remove] username",
+		Run:   reviewerCommand,
+	})
+}
+
+func reviewerCommand(args []string) error {
+	flags := flag.NewFlagSet("reviewer", flag.ContinueOnError)
+	remove := flags.Bool("remove", false, "take the reviewer role away instead")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: reviewer " + commands["reviewer"].Usage)
+	}
+	user, err := GetUserByName(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("unknown user %q", flags.Arg(0))
+	}
+	user.IsReviewer = !*remove
+	user.Store()
+	if user.IsReviewer {
+		fmt.Printf("%s is now a reviewer\n", user.Username)
+	} else {
+		fmt.Printf("%s is no longer a reviewer\n", user.Username)
+	}
+	return nil
+}
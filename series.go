@@ -0,0 +1,232 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"html/template"
+	"strings"
+	"time"
+)
+
+// Series is an ordered group of posts, such as the parts of a tutorial. A
+// post belongs to at most one series.
+type Series struct {
+	Id          bson.ObjectId `bson:"_id,omitempty"`
+	Title       string
+	Slug        string
+	Description string
+	Content     template.HTML
+	Posts       []bson.ObjectId
+	Author      bson.ObjectId `bson:"_author"`
+	Date        time.Time
+}
+
+func (s Series) Url() string {
+	return strings.Join([]string{reverse("series"), s.Slug}, "/")
+}
+
+func (s Series) CanEdit(user User) bool {
+	return (s.Author == user.Id && user.IsBlogAuthor) || user.IsAdmin
+}
+
+func (s Series) GetAuthorAsUser() User {
+	localsession := session.Copy()
+	defer localsession.Close()
+	user := User{}
+	localsession.DB(database).C("users").Find(bson.M{"_id": s.Author}).One(&user)
+	return user
+}
+
+// GetPosts returns the published posts of the series in order. Unlisted
+// posts are left out like everywhere else posts are listed, so they get no
+// series navigation either.
+func (s Series) GetPosts() []BlogPost {
+	return s.posts(listed(bson.M{"published": true}))
+}
+
+// GetMembers returns every post of the series that is not in the trash,
+// drafts included, in order.
+func (s Series) GetMembers() []BlogPost {
+	return s.posts(live(bson.M{}))
+}
+
+func (s Series) posts(query bson.M) []BlogPost {
+	posts := []BlogPost{}
+	if len(s.Posts) == 0 {
+		return posts
+	}
+	localsession := session.Copy()
+	defer localsession.Close()
+	found := []BlogPost{}
+	query["_id"] = bson.M{"$in": s.Posts}
+	localsession.DB(database).C("blogs").Find(query).All(&found)
+
+	byId := map[bson.ObjectId]BlogPost{}
+	for _, post := range found {
+		byId[post.Id] = post
+	}
+	for _, id := range s.Posts {
+		if post, ok := byId[id]; ok {
+			posts = append(posts, post)
+		}
+	}
+	return posts
+}
+
+// Store saves the series, creating it if it has no id yet.
+func (s *Series) Store() error {
+	s.Content = RenderMarkdown(s.Description)
+	localsession := session.Copy()
+	defer localsession.Close()
+	if s.Id == "" {
+		s.Id = bson.NewObjectId()
+		s.Date = time.Now().UTC()
+		return localsession.DB(database).C("series").Insert(s)
+	}
+	return localsession.DB(database).C("series").UpdateId(s.Id, s)
+}
+
+func (s Series) Delete() error {
+	localsession := session.Copy()
+	defer localsession.Close()
+	return localsession.DB(database).C("series").RemoveId(s.Id)
+}
+
+func GetSeriesWithSlug(slug string) (Series, error) {
+	localsession := session.Copy()
+	defer localsession.Close()
+	s := Series{}
+	err := localsession.DB(database).C("series").Find(bson.M{"slug": slug}).One(&s)
+	return s, err
+}
+
+func GetSeriesWithId(id bson.ObjectId) (Series, error) {
+	localsession := session.Copy()
+	defer localsession.Close()
+	s := Series{}
+	err := localsession.DB(database).C("series").FindId(id).One(&s)
+	return s, err
+}
+
+// GetSeriesForPost returns the series the post is part of.
+func GetSeriesForPost(id bson.ObjectId) (Series, error) {
+	localsession := session.Copy()
+	defer localsession.Close()
+	s := Series{}
+	err := localsession.DB(database).C("series").Find(bson.M{"posts": id}).One(&s)
+	return s, err
+}
+
+func GetAllSeries() []Series {
+	localsession := session.Copy()
+	defer localsession.Close()
+	series := []Series{}
+	localsession.DB(database).C("series").Find(nil).Sort("title").All(&series)
+	return series
+}
+
+// SetPosts resolves the slugs of the posts in the series, in order. The
+// user must be able to edit every post, and none may be in another series.
+// Members in the trash can't be listed, so they keep their place and come
+// back with the post if it is restored.
+func (s *Series) SetPosts(slugs []string, user User) error {
+	ids := []bson.ObjectId{}
+	seen := map[bson.ObjectId]bool{}
+	for _, slug := range slugs {
+		post, err := GetBlogPostWithSlug(slug)
+		if err == mgo.ErrNotFound {
+			return fmt.Errorf("there is no post %q", slug)
+		} else if err != nil {
+			return err
+		}
+		if !post.CanEdit(user) {
+			return fmt.Errorf("you can't add %q to a series", post.Title)
+		}
+		if other, err := GetSeriesForPost(post.Id); err == nil && other.Id != s.Id {
+			return fmt.Errorf("%q is already part of %q", post.Title, other.Title)
+		}
+		if !seen[post.Id] {
+			ids = append(ids, post.Id)
+			seen[post.Id] = true
+		}
+	}
+
+	trashed, err := s.trashedPosts()
+	if err != nil {
+		return err
+	}
+	for i, id := range s.Posts {
+		if trashed[id] && !seen[id] {
+			if i > len(ids) {
+				i = len(ids)
+			}
+			ids = append(ids[:i], append([]bson.ObjectId{id}, ids[i:]...)...)
+		}
+	}
+	s.Posts = ids
+	return nil
+}
+
+// trashedPosts returns which members of the series are in the trash.
+func (s Series) trashedPosts() (map[bson.ObjectId]bool, error) {
+	trashed := map[bson.ObjectId]bool{}
+	if len(s.Posts) == 0 {
+		return trashed, nil
+	}
+	localsession := session.Copy()
+	defer localsession.Close()
+	posts := []BlogPost{}
+	err := localsession.DB(database).C("blogs").Find(bson.M{"_id": bson.M{"$in": s.Posts}, "deleted": true}).Select(bson.M{"_id": 1}).All(&posts)
+	for _, post := range posts {
+		trashed[post.Id] = true
+	}
+	return trashed, err
+}
+
+// SeriesNav is the position of a post in its series.
+type SeriesNav struct {
+	Series Series
+	Posts  []BlogPost
+	Index  int
+}
+
+// GetSeriesNav returns where the post is in its series, or nil if it isn't
+// part of one.
+func (post BlogPost) GetSeriesNav() *SeriesNav {
+	s, err := GetSeriesForPost(post.Id)
+	if err != nil {
+		return nil
+	}
+	nav := &SeriesNav{Series: s, Posts: s.GetPosts(), Index: -1}
+	for i, p := range nav.Posts {
+		if p.Id == post.Id {
+			nav.Index = i
+		}
+	}
+	if nav.Index < 0 {
+		return nil
+	}
+	return nav
+}
+
+func (nav SeriesNav) Part() int {
+	return nav.Index + 1
+}
+
+func (nav SeriesNav) Prev() *BlogPost {
+	if nav.Index == 0 {
+		return nil
+	}
+	return &nav.Posts[nav.Index-1]
+}
+
+func (nav SeriesNav) Next() *BlogPost {
+	if nav.Index+1 >= len(nav.Posts) {
+		return nil
+	}
+	return &nav.Posts[nav.Index+1]
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"github.com/gorilla/mux"
+	"gopkg.in/mgo.v2/bson"
+	"net/http"
+	"strings"
+)
+
+func SeriesIndexHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
+	return T("pages/series/index.html", pjax).Execute(w, map[string]interface{}{
+		"ctx":    ctx,
+		"series": GetAllSeries(),
+	})
+}
+
+func SeriesPageHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
+	series, err := GetSeriesWithSlug(mux.Vars(req)["slug"])
+	if err != nil {
+		return NotFoundHandler(w, req, ctx, pjax)
+	}
+	return T("pages/series/read.html", pjax).Execute(w, map[string]interface{}{
+		"ctx":     ctx,
+		"series":  series,
+		"meta":    SiteMeta(series.Title, series.Url(), PlainText(series.Content)),
+		"blogs":   series.GetPosts(),
+		"canEdit": ctx.User != nil && series.CanEdit(*ctx.User),
+	})
+}
+
+func SeriesWriteFormHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
+	if ctx.User == nil || (!ctx.User.IsBlogAuthor && !ctx.User.IsAdmin) {
+		return NotAuthedHandler(w, req, ctx, pjax)
+	}
+
+	series := Series{}
+	slugs := []string{}
+	if id := req.FormValue("id"); id != "" {
+		if !bson.IsObjectIdHex(id) {
+			return BadRequestHandler(w, req, ctx, pjax)
+		}
+		series, err = GetSeriesWithId(bson.ObjectIdHex(id))
+		if err != nil {
+			return NotFoundHandler(w, req, ctx, pjax)
+		}
+		if !series.CanEdit(*ctx.User) {
+			return NotAuthedHandler(w, req, ctx, pjax)
+		}
+		for _, post := range series.GetMembers() {
+			slugs = append(slugs, post.Slug)
+		}
+	}
+	if req.Method == "POST" {
+		series.Title = req.FormValue("title")
+		series.Description = req.FormValue("description")
+		slugs = strings.Fields(req.FormValue("posts"))
+	}
+
+	return T("pages/series/write.html", pjax).Execute(w, map[string]interface{}{
+		"ctx":    ctx,
+		"series": series,
+		"posts":  strings.Join(slugs, "\n"),
+		"blogs":  GetAllBlogsByAuthor(ctx.User, 50),
+	})
+}
+
+func SeriesWriteHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
+	if ctx.User == nil || (!ctx.User.IsBlogAuthor && !ctx.User.IsAdmin) {
+		return NotAuthedHandler(w, req, ctx, pjax)
+	}
+
+	series := Series{Author: ctx.User.Id}
+	if id := req.FormValue("id"); id != "" {
+		if !bson.IsObjectIdHex(id) {
+			return BadRequestHandler(w, req, ctx, pjax)
+		}
+		series, err = GetSeriesWithId(bson.ObjectIdHex(id))
+		if err != nil {
+			return NotFoundHandler(w, req, ctx, pjax)
+		}
+		if !series.CanEdit(*ctx.User) {
+			return NotAuthedHandler(w, req, ctx, pjax)
+		}
+	}
+
+	series.Title = strings.TrimSpace(req.FormValue("title"))
+	series.Description = req.FormValue("description")
+	if series.Title == "" {
+		ctx.Session.AddFlash("A series needs a title.")
+		return SeriesWriteFormHandler(w, req, ctx, pjax)
+	}
+	if series.Slug == "" {
+		series.Slug = Slugify(series.Title)
+		if _, err := GetSeriesWithSlug(series.Slug); err == nil {
+			ctx.Session.AddFlash(fmt.Sprintf("There already is a series called %q.", series.Title))
+			return SeriesWriteFormHandler(w, req, ctx, pjax)
+		}
+	}
+	if err = series.SetPosts(strings.Fields(req.FormValue("posts")), *ctx.User); err != nil {
+		ctx.Session.AddFlash(err.Error())
+		return SeriesWriteFormHandler(w, req, ctx, pjax)
+	}
+
+	if err = series.Store(); err != nil {
+		return err
+	}
+	http.Redirect(w, req, series.Url(), http.StatusSeeOther)
+	return nil
+}
+
+func SeriesDeleteHandler(w http.ResponseWriter, req *http.Request, ctx *Context, pjax bool) (err error) {
+	id := mux.Vars(req)["id"]
+	if !bson.IsObjectIdHex(id) {
+		return BadRequestHandler(w, req, ctx, pjax)
+	}
+	series, err := GetSeriesWithId(bson.ObjectIdHex(id))
+	if err != nil {
+		return NotFoundHandler(w, req, ctx, pjax)
+	}
+	if ctx.User == nil || !series.CanEdit(*ctx.User) {
+		return NotAuthedHandler(w, req, ctx, pjax)
+	}
+
+	if err = series.Delete(); err != nil {
+		return err
+	}
+	http.Redirect(w, req, reverse("series"), http.StatusSeeOther)
+	return nil
+}
@@ -0,0 +1,260 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+// A Shortcode expands the arguments of a {{< name args >}} tag in post
+// Markdown into HTML. Its output is sanitized with the rest of the post.
+type Shortcode func(args []string) (template.HTML, error)
+
+var shortcodes = map[string]Shortcode{}
+
+// RegisterShortcode makes fn available in post Markdown as {{< name >}}.
+func RegisterShortcode(name string, fn Shortcode) {
+	shortcodes[name] = fn
+}
+
+func init() {
+	RegisterShortcode("youtube", YoutubeShortcode)
+	RegisterShortcode("gist", GistShortcode)
+	RegisterShortcode("figure", FigureShortcode)
+	RegisterShortcode("post", PostShortcode)
+}
+
+var (
+	shortcodeTag     = regexp.MustCompile(`\{\{<\s*(.*?)\s*>\}\}`)
+	shortcodeFence   = regexp.MustCompile("^\\s{0,3}(```|~~~)")
+	shortcodeIndent  = regexp.MustCompile("^(    |\t)")
+	youtubeVideoId   = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+	gistId           = regexp.MustCompile(`^[A-Za-z0-9-]+/[0-9a-f]+$`)
+	errShortcodeArgs = errors.New("wrong number of arguments")
+)
+
+// ExpandShortcodes replaces every shortcode tag in Markdown source with
+// the HTML it expands to. Tags in code, with an unknown name or that fail
+// to expand are left alone.
+func ExpandShortcodes(source string) string {
+	return replaceShortcodes(source, func(tag string, block bool) string {
+		expanded, ok := expandShortcode(tag)
+		if !ok {
+			return tag
+		}
+		// A tag on a line of its own becomes an HTML block.
+		if block {
+			return "\n<div>" + expanded + "</div>\n"
+		}
+		return expanded
+	})
+}
+
+// StripShortcodes removes every shortcode tag with a known name from
+// Markdown source, for text that must not run shortcodes such as
+// summaries. Tags in code are left alone.
+func StripShortcodes(source string) string {
+	return replaceShortcodes(source, func(tag string, block bool) string {
+		fields := splitShortcodeArgs(shortcodeTag.FindStringSubmatch(tag)[1])
+		if len(fields) == 0 {
+			return tag
+		}
+		if _, ok := shortcodes[fields[0]]; !ok {
+			return tag
+		}
+		return ""
+	})
+}
+
+// replaceShortcodes replaces every shortcode tag outside code with what
+// replace returns for it, so tags can be shown in fenced and indented code
+// blocks and in code spans. block is whether the tag is on a line of its
+// own.
+func replaceShortcodes(source string, replace func(tag string, block bool) string) string {
+	lines := strings.Split(source, "\n")
+	fence := ""
+	// Indented code can't interrupt a paragraph, so it starts after a
+	// blank line or another block.
+	afterBlock, indented := true, false
+	for i, line := range lines {
+		if fence == "" {
+			indented = (afterBlock || indented) && shortcodeIndent.MatchString(line)
+		}
+		afterBlock = strings.TrimSpace(line) == ""
+		if m := shortcodeFence.FindStringSubmatch(line); m != nil && !indented {
+			if fence == "" {
+				fence = m[1]
+			} else if fence == m[1] {
+				fence = ""
+				afterBlock = true
+			}
+			continue
+		}
+		if fence != "" || indented || !strings.Contains(line, "{{<") {
+			continue
+		}
+		lines[i] = replaceOutsideSpans(line, replace)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// replaceOutsideSpans replaces the shortcode tags of a line that are not
+// in a code span.
+func replaceOutsideSpans(line string, replace func(tag string, block bool) string) string {
+	spans := codeSpans(line)
+	block := strings.TrimSpace(line)
+	var out strings.Builder
+	last := 0
+	for _, m := range shortcodeTag.FindAllStringIndex(line, -1) {
+		inSpan := false
+		for _, span := range spans {
+			inSpan = inSpan || m[0] < span[1] && m[1] > span[0]
+		}
+		if inSpan {
+			continue
+		}
+		tag := line[m[0]:m[1]]
+		out.WriteString(line[last:m[0]])
+		out.WriteString(replace(tag, tag == block))
+		last = m[1]
+	}
+	out.WriteString(line[last:])
+	return out.String()
+}
+
+// codeSpans returns where the code spans of a line start and end. A span
+// opens with a run of backticks and closes at the next run of as many.
+func codeSpans(line string) [][2]int {
+	spans := [][2]int{}
+	open, width := -1, 0
+	for i := 0; i < len(line); {
+		if line[i] != '`' {
+			i++
+			continue
+		}
+		run := i
+		for i < len(line) && line[i] == '`' {
+			i++
+		}
+		switch {
+		case open < 0:
+			open, width = run, i-run
+		case i-run == width:
+			spans = append(spans, [2]int{open, i})
+			open = -1
+		}
+	}
+	return spans
+}
+
+func expandShortcode(tag string) (string, bool) {
+	fields := splitShortcodeArgs(shortcodeTag.FindStringSubmatch(tag)[1])
+	if len(fields) == 0 {
+		return "", false
+	}
+	fn, ok := shortcodes[fields[0]]
+	if !ok {
+		return "", false
+	}
+	out, err := fn(fields[1:])
+	if err != nil {
+		log.Warning(fmt.Sprintf("shortcode %s: %s", fields[0], err))
+		return "", false
+	}
+	return string(out), true
+}
+
+// splitShortcodeArgs splits on whitespace, keeping double quoted strings
+// together.
+func splitShortcodeArgs(s string) []string {
+	args := []string{}
+	var current strings.Builder
+	quoted, started := false, false
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			started = true
+		case !quoted && (r == ' ' || r == '\t'):
+			if started {
+				args = append(args, current.String())
+				current.Reset()
+				started = false
+			}
+		default:
+			current.WriteRune(r)
+			started = true
+		}
+	}
+	if started {
+		args = append(args, current.String())
+	}
+	return args
+}
+
+// YoutubeShortcode links to a video on youtube-nocookie.com instead of
+// embedding a player, so nothing is loaded from YouTube until the reader
+// clicks it. {{< youtube id >}}
+func YoutubeShortcode(args []string) (template.HTML, error) {
+	if len(args) != 1 {
+		return "", errShortcodeArgs
+	}
+	if !youtubeVideoId.MatchString(args[0]) {
+		return "", fmt.Errorf("invalid video id %q", args[0])
+	}
+	return template.HTML(fmt.Sprintf(
+		`<a class="embed embed-youtube" href="https://www.youtube-nocookie.com/embed/%s?autoplay=1">Watch on YouTube</a>`,
+		args[0])), nil
+}
+
+// GistShortcode links to a GitHub gist. {{< gist user/id >}}
+func GistShortcode(args []string) (template.HTML, error) {
+	if len(args) != 1 {
+		return "", errShortcodeArgs
+	}
+	if !gistId.MatchString(args[0]) {
+		return "", fmt.Errorf("invalid gist %q", args[0])
+	}
+	return template.HTML(fmt.Sprintf(
+		`<a class="embed embed-gist" href="https://gist.github.com/%s">View gist %s on GitHub</a>`,
+		args[0], args[0])), nil
+}
+
+// FigureShortcode renders an image with an optional caption.
+// {{< figure src "caption" >}}
+func FigureShortcode(args []string) (template.HTML, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return "", errShortcodeArgs
+	}
+	src := html.EscapeString(args[0])
+	if len(args) == 1 {
+		return template.HTML(fmt.Sprintf(`<figure><img src="%s"></figure>`, src)), nil
+	}
+	caption := html.EscapeString(args[1])
+	return template.HTML(fmt.Sprintf(`<figure><img src="%s" alt="%s"><figcaption>%s</figcaption></figure>`,
+		src, caption, caption)), nil
+}
+
+// PostShortcode links to another published post. {{< post slug >}}
+// Its text comes from what is stored for the post, so posts linking to
+// each other can't expand each other without end.
+func PostShortcode(args []string) (template.HTML, error) {
+	if len(args) != 1 {
+		return "", errShortcodeArgs
+	}
+	post, err := GetBlogPostWithSlug(args[0])
+	if err != nil {
+		return "", err
+	}
+	if !post.Published {
+		return "", fmt.Errorf("post %q is not published", args[0])
+	}
+	return template.HTML(fmt.Sprintf(`<a class="embed embed-post" href="%s"><strong>%s</strong><br>%s</a>`,
+		post.SlugUrl(), html.EscapeString(post.Title), html.EscapeString(post.PlainSummary()))), nil
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"html/template"
+	"testing"
+)
+
+func TestReplaceShortcodes(t *testing.T) {
+	RegisterShortcode("test", func(args []string) (template.HTML, error) {
+		return "<b>test</b>", nil
+	})
+	defer delete(shortcodes, "test")
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{"inline", "a {{< test >}} b", "a <b>test</b> b"},
+		{"block", "{{< test >}}", "\n<div><b>test</b></div>\n"},
+		{"unknown", "a {{< nope >}} b", "a {{< nope >}} b"},
+		{"fenced", "```\n{{< test >}}\n```", "```\n{{< test >}}\n```"},
+		{"code span", "use `{{< test >}}` here", "use `{{< test >}}` here"},
+		{"double backtick span", "use ``{{< test >}} ` x`` and {{< test >}}", "use ``{{< test >}} ` x`` and <b>test</b>"},
+		{"after a code span", "`x` {{< test >}}", "`x` <b>test</b>"},
+		{"unclosed backtick", "`x {{< test >}}", "`x <b>test</b>"},
+		{"indented", "text\n\n    {{< test >}}\n\nmore", "text\n\n    {{< test >}}\n\nmore"},
+		{"tab indented", "\t{{< test >}}", "\t{{< test >}}"},
+		{"indented after blank inside code", "    a\n\n    {{< test >}}", "    a\n\n    {{< test >}}"},
+		{"paragraph continuation", "text\n    {{< test >}} more", "text\n    <b>test</b> more"},
+		{"after indented code", "    code\n{{< test >}}", "    code\n\n<div><b>test</b></div>\n"},
+	}
+	for _, test := range tests {
+		if got := ExpandShortcodes(test.source); got != test.want {
+			t.Errorf("%s: ExpandShortcodes(%q) = %q, want %q", test.name, test.source, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,381 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	"gopkg.in/mgo.v2/bson"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Social cards are the images shown when a page is shared, at the size
+// Open Graph and Twitter recommend.
+const (
+	SocialCardWidth  = 1200
+	SocialCardHeight = 630
+	SocialCardFolder = "./static/img/cards/"
+)
+
+const (
+	cardPadding    = 72
+	cardAvatarSize = 96
+	cardTitleLines = 3
+)
+
+// cardTitleSizes are the title font sizes tried in turn until the title
+// fits in cardTitleLines lines.
+var cardTitleSizes = []float64{80, 68, 56}
+
+// cardFallbackColor is the background of cards whose image can't be read.
+var cardFallbackColor = color.RGBA{0x37, 0x47, 0x4f, 0xff}
+
+var (
+	cardFontBold    = mustParseFont(gobold.TTF)
+	cardFontRegular = mustParseFont(goregular.TTF)
+	cardLock        sync.Mutex
+	cardClient      = &http.Client{Timeout: 5 * time.Second}
+)
+
+func mustParseFont(ttf []byte) *opentype.Font {
+	f, err := opentype.Parse(ttf)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+func cardFace(f *opentype.Font, size float64) font.Face {
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		panic(err)
+	}
+	return face
+}
+
+// CardImagePath is the path the social card of the post is served at.
+func (post BlogPost) CardImagePath() string {
+	return reverse("card") + "/" + post.Id.Hex() + ".png"
+}
+
+// CardImageUrl is the absolute URL of the social card of the post, which
+// changes with every version so shared links pick up edits.
+func (post BlogPost) CardImageUrl() string {
+	return "https://" + config.Site.Domain + post.CardImagePath() + "?v=" + strconv.Itoa(post.Version)
+}
+
+// SiteCardPath is the path the social card of the site itself is served
+// at, for pages that are not posts.
+func SiteCardPath() string {
+	return reverse("card") + "/site.png"
+}
+
+func SiteCardUrl() string {
+	return "https://" + config.Site.Domain + SiteCardPath() + "?v=" + siteCardVersion()
+}
+
+func siteCardVersion() string {
+	sum := sha1.Sum([]byte(config.Site.Title + "\x00" + config.Site.Description + "\x00" + config.Site.DefaultImage))
+	return fmt.Sprintf("%x", sum[:4])
+}
+
+// PostCardImage returns the file holding the social card of the post,
+// drawing it first if this version of the post has none yet.
+func PostCardImage(post BlogPost) (string, error) {
+	return cachedCard(post.Id.Hex(), strconv.Itoa(post.Version), func() image.Image {
+		var background image.Image
+		if post.HasImage() {
+			background = loadCardImage(post.HeaderImage())
+		}
+		return DrawSocialCard(post.Title, post.GetAuthorAsUser(), background)
+	})
+}
+
+// SiteCardImage returns the file holding the social card of the site.
+func SiteCardImage() (string, error) {
+	return cachedCard("site", siteCardVersion(), func() image.Image {
+		return DrawSocialCard(config.Site.Description, User{}, loadCardImage(DefaultHeaderImage()))
+	})
+}
+
+// cachedCard returns the card called name at version, drawing it if
+// needed and removing the cards of older versions.
+func cachedCard(name string, version string, render func() image.Image) (string, error) {
+	cardLock.Lock()
+	defer cardLock.Unlock()
+
+	file := SocialCardFolder + name + "." + version + ".png"
+	if _, err := os.Stat(file); err == nil {
+		return file, nil
+	}
+	if err := os.MkdirAll(SocialCardFolder, 0775); err != nil {
+		return "", err
+	}
+	out, err := os.Create(file + ".tmp")
+	if err != nil {
+		return "", err
+	}
+	if err = png.Encode(out, render()); err != nil {
+		out.Close()
+		os.Remove(file + ".tmp")
+		return "", err
+	}
+	out.Close()
+	if err = os.Rename(file+".tmp", file); err != nil {
+		return "", err
+	}
+
+	stale, _ := filepath.Glob(SocialCardFolder + name + ".*.png")
+	for _, old := range stale {
+		if filepath.Clean(old) != filepath.Clean(file) {
+			os.Remove(old)
+		}
+	}
+	return file, nil
+}
+
+// loadCardImage reads a header image from disk, or returns nil if it is
+// not one of ours or can't be decoded.
+func loadCardImage(src string) image.Image {
+	if !strings.HasPrefix(src, "/assets/") {
+		return nil
+	}
+	in, err := os.Open("./static/" + strings.TrimPrefix(src, "/assets/"))
+	if err != nil {
+		return nil
+	}
+	defer in.Close()
+	img, _, err := image.Decode(in)
+	if err != nil {
+		return nil
+	}
+	return img
+}
+
+// DrawSocialCard draws a card with the title, the author and the site
+// title over the background image, or over the dominant color of the
+// default header image if there is none. The author is left out if it has
+// no id.
+func DrawSocialCard(title string, author User, background image.Image) image.Image {
+	card := image.NewRGBA(image.Rect(0, 0, SocialCardWidth, SocialCardHeight))
+	if background != nil {
+		coverImage(card, background)
+		draw.Draw(card, card.Bounds(), &image.Uniform{color.RGBA{0, 0, 0, 0x90}}, image.ZP, draw.Over)
+	} else {
+		fill := cardFallbackColor
+		if img := loadCardImage(DefaultHeaderImage()); img != nil {
+			fill = darken(DominantColor(img))
+		}
+		draw.Draw(card, card.Bounds(), &image.Uniform{fill}, image.ZP, draw.Src)
+	}
+
+	width := SocialCardWidth - 2*cardPadding
+	var face font.Face
+	var lines []string
+	for _, size := range cardTitleSizes {
+		face = cardFace(cardFontBold, size)
+		lines = wrapText(face, title, width)
+		if len(lines) <= cardTitleLines {
+			break
+		}
+	}
+	if len(lines) > cardTitleLines {
+		lines = lines[:cardTitleLines]
+		lines[cardTitleLines-1] = fitText(face, lines[cardTitleLines-1]+"…", width)
+	}
+	lineHeight := face.Metrics().Height.Ceil()
+	y := cardPadding + face.Metrics().Ascent.Ceil()
+	for _, line := range lines {
+		drawText(card, face, line, cardPadding, y)
+		y += lineHeight
+	}
+
+	small := cardFace(cardFontRegular, 32)
+	baseline := SocialCardHeight - cardPadding - (cardAvatarSize-small.Metrics().Ascent.Ceil())/2
+	if author.Id != "" {
+		top := SocialCardHeight - cardPadding - cardAvatarSize
+		if avatar, err := cardAvatar(author); err == nil {
+			r := image.Rect(cardPadding, top, cardPadding+cardAvatarSize, top+cardAvatarSize)
+			scaled := image.NewRGBA(image.Rect(0, 0, cardAvatarSize, cardAvatarSize))
+			draw.CatmullRom.Scale(scaled, scaled.Bounds(), avatar, avatar.Bounds(), draw.Src, nil)
+			draw.DrawMask(card, r, scaled, image.ZP, &circle{cardAvatarSize / 2}, image.ZP, draw.Over)
+		}
+		drawText(card, small, author.DisplayName, cardPadding+cardAvatarSize+24, baseline)
+	}
+	site := config.Site.Title
+	drawText(card, small, site, SocialCardWidth-cardPadding-font.MeasureString(small, site).Ceil(), baseline)
+	return card
+}
+
+// cardAvatar returns the avatar the author shows on the site: their
+// Gravatar if they use it and it can be fetched, or else their identicon.
+func cardAvatar(author User) (image.Image, error) {
+	if author.UsingGravatar {
+		if avatar, err := fetchImage(author.Gravatar()); err == nil {
+			return avatar, nil
+		}
+	}
+	return GenerateIdenticon(author.GetInfoHash())
+}
+
+// fetchImage downloads and decodes the image at u.
+func fetchImage(u string) (image.Image, error) {
+	resp, err := cardClient.Get(u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", u, resp.Status)
+	}
+	img, _, err := image.Decode(io.LimitReader(resp.Body, 4<<20))
+	return img, err
+}
+
+// coverImage scales src to cover dst, cropping whatever overflows.
+func coverImage(dst *image.RGBA, src image.Image) {
+	sb, db := src.Bounds(), dst.Bounds()
+	crop := sb
+	if sb.Dx()*db.Dy() > sb.Dy()*db.Dx() {
+		w := sb.Dy() * db.Dx() / db.Dy()
+		crop.Min.X += (sb.Dx() - w) / 2
+		crop.Max.X = crop.Min.X + w
+	} else {
+		h := sb.Dx() * db.Dy() / db.Dx()
+		crop.Min.Y += (sb.Dy() - h) / 2
+		crop.Max.Y = crop.Min.Y + h
+	}
+	draw.ApproxBiLinear.Scale(dst, db, src, crop, draw.Src, nil)
+}
+
+// DominantColor returns the average of the most common of 4096 color
+// buckets in img, sampling a grid of at most 64×64 pixels.
+func DominantColor(img image.Image) color.RGBA {
+	b := img.Bounds()
+	stepX, stepY := b.Dx()/64+1, b.Dy()/64+1
+	type bucket struct{ r, g, b, n uint32 }
+	buckets := map[uint32]*bucket{}
+	var best *bucket
+	for y := b.Min.Y; y < b.Max.Y; y += stepY {
+		for x := b.Min.X; x < b.Max.X; x += stepX {
+			r, g, bl, _ := img.At(x, y).RGBA()
+			key := r>>12<<8 | g>>12<<4 | bl>>12
+			bk := buckets[key]
+			if bk == nil {
+				bk = &bucket{}
+				buckets[key] = bk
+			}
+			bk.r, bk.g, bk.b, bk.n = bk.r+r>>8, bk.g+g>>8, bk.b+bl>>8, bk.n+1
+			if best == nil || bk.n > best.n {
+				best = bk
+			}
+		}
+	}
+	if best == nil {
+		return cardFallbackColor
+	}
+	return color.RGBA{uint8(best.r / best.n), uint8(best.g / best.n), uint8(best.b / best.n), 0xff}
+}
+
+// darken scales c down until white text is readable on it.
+func darken(c color.RGBA) color.RGBA {
+	const maxLuma = 96
+	luma := (299*uint32(c.R) + 587*uint32(c.G) + 114*uint32(c.B)) / 1000
+	if luma <= maxLuma {
+		return c
+	}
+	scale := func(v uint8) uint8 { return uint8(uint32(v) * maxLuma / luma) }
+	return color.RGBA{scale(c.R), scale(c.G), scale(c.B), 0xff}
+}
+
+// wrapText breaks s into lines no wider than width.
+func wrapText(face font.Face, s string, width int) []string {
+	lines := []string{}
+	line := ""
+	for _, word := range strings.Fields(s) {
+		next := word
+		if line != "" {
+			next = line + " " + word
+		}
+		if line != "" && font.MeasureString(face, next).Ceil() > width {
+			lines = append(lines, line)
+			next = word
+		}
+		line = next
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	for i := range lines {
+		lines[i] = fitText(face, lines[i], width)
+	}
+	return lines
+}
+
+// fitText cuts s so it is no wider than width, keeping a trailing ellipsis.
+func fitText(face font.Face, s string, width int) string {
+	if font.MeasureString(face, s).Ceil() <= width {
+		return s
+	}
+	runes := []rune(strings.TrimSuffix(s, "…"))
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		cut := strings.TrimRight(string(runes), " ") + "…"
+		if font.MeasureString(face, cut).Ceil() <= width {
+			return cut
+		}
+	}
+	return ""
+}
+
+func drawText(dst draw.Image, face font.Face, s string, x int, y int) {
+	d := &font.Drawer{Dst: dst, Src: image.White, Face: face, Dot: fixed.P(x, y)}
+	d.DrawString(s)
+}
+
+// circle is a mask for a circle of radius r at the origin's corner.
+type circle struct {
+	r int
+}
+
+func (c *circle) ColorModel() color.Model {
+	return color.AlphaModel
+}
+
+func (c *circle) Bounds() image.Rectangle {
+	return image.Rect(0, 0, 2*c.r, 2*c.r)
+}
+
+func (c *circle) At(x, y int) color.Color {
+	dx, dy := float64(x-c.r)+0.5, float64(y-c.r)+0.5
+	if dx*dx+dy*dy < float64(c.r*c.r) {
+		return color.Alpha{0xff}
+	}
+	return color.Alpha{0}
+}
+
+// cardPost returns the published post a card is asked for by id.
+func cardPost(name string) (BlogPost, bool) {
+	if !bson.IsObjectIdHex(name) {
+		return BlogPost{}, false
+	}
+	post, err := GetBlogPostWithId(bson.ObjectIdHex(name))
+	return post, err == nil && post.Published
+}
@@ -0,0 +1,250 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"html"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// staticOnlyRoutes are the named routes that only work on the dynamic site
+// and are neither crawled nor written out, along with everything below them.
+var staticOnlyRoutes = []string{
+	"login", "register", "logout",
+	"blog-write", "blog-edit", "blog-render", "blog-autosave", "blog-trash", "blog-export", "blog-gitsync",
+	"blog-share", "blog-preview", "blog-pin",
+	"blog-reviews", "blog-review", "blog-transition", "blog-comment",
+	"series-write", "series-delete",
+	"oembed",
+}
+
+var localLink = regexp.MustCompile(`(?:href|src)="(/[^"]*)"`)
+
+var redirectPage = `<!DOCTYPE html>
+<html><head><meta charset="utf-8"><link rel="canonical" href="%[1]s"><meta http-equiv="refresh" content="0; url=%[1]s"></head>
+<body><a href="%[1]s">%[1]s</a></body></html>
+`
+
+func init() {
+	RegisterCommand("static", Command{
+		Usage: "[-o dir]",
+		Run:   staticCommand,
+	})
+}
+
+// staticSite crawls the site through the router, starting from every
+// public route, and writes each response to a directory a plain web server
+// or CDN can serve.
+type staticSite struct {
+	out       string
+	queued    map[string]bool
+	queue     []string
+	urls      map[string]string
+	redirects map[string]string
+	pages     []string
+}
+
+// ExportStaticSite writes the public site to out, with links rewritten to
+// the paths the pages are written to.
+func ExportStaticSite(out string) error {
+	site := &staticSite{
+		out:       out,
+		queued:    map[string]bool{},
+		urls:      map[string]string{},
+		redirects: map[string]string{},
+	}
+
+	site.enqueue(reverse("index"), reverse("bio"), reverse("clock"), reverse("blog"), reverse("series"), reverse("code-theme", "theme", CodeTheme()))
+	site.enqueue(SiteCardPath())
+	for _, post := range GetAllBlogs(false) {
+		site.enqueue(post.SlugUrl(), post.IdUrl(), post.CardImagePath())
+	}
+
+	for len(site.queue) > 0 {
+		u := site.queue[0]
+		site.queue = site.queue[1:]
+		if err := site.fetch(u); err != nil {
+			return err
+		}
+	}
+
+	for from, to := range site.redirects {
+		if target, ok := site.urls[to]; ok {
+			site.urls[from] = target
+		}
+	}
+	if err := site.rewriteLinks(); err != nil {
+		return err
+	}
+	return copyDir("./static", filepath.Join(out, "assets"))
+}
+
+func (site *staticSite) enqueue(urls ...string) {
+	for _, u := range urls {
+		if i := strings.Index(u, "#"); i >= 0 {
+			u = u[:i]
+		}
+		if u == "" || site.queued[u] || !site.crawlable(u) {
+			continue
+		}
+		site.queued[u] = true
+		site.queue = append(site.queue, u)
+	}
+}
+
+// crawlable reports whether u is a page of the public site that can be
+// written to a file. The only query allowed is a page number.
+func (site *staticSite) crawlable(u string) bool {
+	parsed, err := url.Parse(u)
+	if err != nil || parsed.Host != "" || !strings.HasPrefix(parsed.Path, "/") || strings.HasPrefix(parsed.Path, "/assets/") {
+		return false
+	}
+	for key := range parsed.Query() {
+		if key != "page" {
+			return false
+		}
+	}
+	for _, name := range staticOnlyRoutes {
+		if base := reverse(name); parsed.Path == base || strings.HasPrefix(parsed.Path, base+"/") {
+			return false
+		}
+	}
+	return true
+}
+
+// staticPath returns the URL u is served at on the static site, and the
+// file that holds it.
+func (site *staticSite) staticPath(u string, isHtml bool) (string, string) {
+	parsed, _ := url.Parse(u)
+	p := parsed.Path
+	if page := parsed.Query().Get("page"); page != "" {
+		p = path.Join(p, "page", page)
+	}
+	if !isHtml {
+		return p, filepath.Join(site.out, filepath.FromSlash(p))
+	}
+	if !strings.HasSuffix(p, "/") {
+		p += "/"
+	}
+	return p, filepath.Join(site.out, filepath.FromSlash(p), "index.html")
+}
+
+func (site *staticSite) fetch(u string) error {
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", u, nil))
+
+	switch {
+	case rec.Code >= 300 && rec.Code < 400:
+		target := rec.Header().Get("Location")
+		if !site.crawlable(target) {
+			return nil
+		}
+		site.redirects[u] = target
+		site.enqueue(target)
+		static, file := site.staticPath(u, true)
+		site.urls[u] = static
+		targetStatic, _ := site.staticPath(target, true)
+		return writeFile(file, []byte(fmt.Sprintf(redirectPage, html.EscapeString(targetStatic))))
+	case rec.Code != http.StatusOK:
+		log.Warning(fmt.Sprintf("static: %s returned %d, skipping", u, rec.Code))
+		return nil
+	}
+
+	isHtml := strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html")
+	static, file := site.staticPath(u, isHtml)
+	site.urls[u] = static
+	body := rec.Body.Bytes()
+	if isHtml {
+		site.pages = append(site.pages, file)
+		for _, m := range localLink.FindAllSubmatch(body, -1) {
+			site.enqueue(html.UnescapeString(string(m[1])))
+		}
+	}
+	return writeFile(file, body)
+}
+
+// rewriteLinks points every link to a crawled page at its static path.
+func (site *staticSite) rewriteLinks() error {
+	pairs := []string{}
+	for from, to := range site.urls {
+		if from == to {
+			continue
+		}
+		for _, attr := range []string{"href", "src"} {
+			pairs = append(pairs,
+				attr+`="`+html.EscapeString(from)+`"`, attr+`="`+to+`"`,
+				attr+`="`+html.EscapeString(from)+`#`, attr+`="`+to+`#`)
+		}
+	}
+	replacer := strings.NewReplacer(pairs...)
+	for _, file := range site.pages {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if err = ioutil.WriteFile(file, []byte(replacer.Replace(string(content))), 0664); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeFile(file string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0775); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, content, 0664)
+}
+
+func copyDir(src string, dest string) error {
+	return filepath.Walk(src, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, 0775)
+		}
+		in, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		out, err := os.Create(target)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+		_, err = io.Copy(out, in)
+		return err
+	})
+}
+
+func staticCommand(args []string) error {
+	flags := flag.NewFlagSet("static", flag.ContinueOnError)
+	out := flags.String("o", "public", "directory to write the site to")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := ExportStaticSite(*out); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote the static site to %s\n", *out)
+	return nil
+}
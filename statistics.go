@@ -147,8 +147,12 @@ func LoadStats() error {
 	return nil
 }
 
-// LogRequest increments all appropriate stat counters, then writes to the access log
+// LogRequest increments all appropriate stat counters, then writes to the access log.
+// Nothing is logged unless the server is running, i.e. the access log is open.
 func LogRequest(req *http.Request, bytes int, response int) {
+	if AccessLog == nil {
+		return
+	}
 	ip := strings.Split(req.RemoteAddr, ":")[0]
 	if ip == "127.0.0.1" {
 		if req.Header.Get("X-Real-IP") != "" {
@@ -177,6 +181,7 @@ func LogRequest(req *http.Request, bytes int, response int) {
 		if req.URL.Query().Get("ref") != "" {
 			Referers[req.URL.Query().Get("ref")]++
 		}
+		CountPostView(req, response)
 	}
 	BytesServed += int64(bytes)
 	RequestsServed++
@@ -33,6 +33,17 @@ var funcs = template.FuncMap{
 	"flargenum":         flargenum,
 	"join":              join,
 	"json":              indentjson,
+	"codetheme":         codetheme,
+	"inc":               inc,
+	"statename":         StateName,
+}
+
+func inc(i int) int {
+	return i + 1
+}
+
+func codetheme() string {
+	return reverse("code-theme", "theme", CodeTheme())
 }
 
 func indentjson(i interface{}) string {
@@ -0,0 +1,131 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"gopkg.in/mgo.v2/bson"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// TrashPurgeInterval is how often posts past the trash retention are purged.
+const TrashPurgeInterval = time.Hour
+
+// Trash moves the post to the trash, hiding it from every other query.
+func (post *BlogPost) Trash(user User) error {
+	post.Deleted = true
+	post.DateDeleted = time.Now().UTC()
+	post.DeletedBy = user.Id
+	err := post.update(nil, bson.M{"$set": bson.M{
+		"deleted":     post.Deleted,
+		"datedeleted": post.DateDeleted,
+		"_deleter":    post.DeletedBy,
+	}})
+	ScheduleRelatedRefresh()
+	return err
+}
+
+// Restore takes the post back out of the trash.
+func (post *BlogPost) Restore() error {
+	post.Deleted = false
+	post.DateDeleted = time.Time{}
+	post.DeletedBy = ""
+	err := post.update(nil, bson.M{
+		"$set":   bson.M{"deleted": false},
+		"$unset": bson.M{"datedeleted": "", "_deleter": ""},
+	})
+	ScheduleRelatedRefresh()
+	return err
+}
+
+// Purge permanently removes the post, its images and its social cards.
+func (post BlogPost) Purge() error {
+	localsession := session.Copy()
+	defer localsession.Close()
+	err := localsession.DB(database).C("blogs").RemoveId(post.Id)
+	if err != nil {
+		return err
+	}
+	cards, _ := filepath.Glob(SocialCardFolder + post.Id.Hex() + ".*.png")
+	for _, card := range cards {
+		if err = os.Remove(card); err != nil {
+			return err
+		}
+	}
+	return os.RemoveAll(blogImageFolder(post.Id))
+}
+
+// PurgeDate is when the post will be purged from the trash automatically.
+// It is the zero time if trashed posts are kept forever.
+func (post BlogPost) PurgeDate() time.Time {
+	if config.Blog.TrashRetentionDays <= 0 {
+		return time.Time{}
+	}
+	return post.DateDeleted.AddDate(0, 0, config.Blog.TrashRetentionDays)
+}
+
+func GetTrashedBlogPost(id bson.ObjectId) (BlogPost, error) {
+	localsession := session.Copy()
+	defer localsession.Close()
+	post := BlogPost{}
+	err := localsession.DB(database).C("blogs").Find(bson.M{"_id": id, "deleted": true}).One(&post)
+	return post, err
+}
+
+// SlugInTrash is whether a trashed post has the slug.
+func SlugInTrash(slug string) (bool, error) {
+	localsession := session.Copy()
+	defer localsession.Close()
+	count, err := localsession.DB(database).C("blogs").Find(bson.M{"slug": slug, "deleted": true}).Count()
+	return count > 0, err
+}
+
+// GetTrash returns the trashed posts the user may restore or purge, most
+// recently trashed first.
+func GetTrash(user *User) []BlogPost {
+	localsession := session.Copy()
+	defer localsession.Close()
+	query := bson.M{"deleted": true}
+	if !user.IsAdmin {
+		query["_author"] = user.Id
+	}
+	blogs := []BlogPost{}
+	localsession.DB(database).C("blogs").Find(query).Sort("-datedeleted").All(&blogs)
+	return blogs
+}
+
+// PurgeExpiredTrash purges every post that has been in the trash for longer
+// than the configured retention.
+func PurgeExpiredTrash() error {
+	if config.Blog.TrashRetentionDays <= 0 {
+		return nil
+	}
+	localsession := session.Copy()
+	defer localsession.Close()
+	cutoff := time.Now().UTC().AddDate(0, 0, -config.Blog.TrashRetentionDays)
+	expired := []BlogPost{}
+	err := localsession.DB(database).C("blogs").Find(bson.M{"deleted": true, "datedeleted": bson.M{"$lt": cutoff}}).All(&expired)
+	if err != nil {
+		return err
+	}
+	for _, post := range expired {
+		if err := post.Purge(); err != nil {
+			return err
+		}
+		log.Info(fmt.Sprintf("Purged %q from the trash", post.Title))
+	}
+	return nil
+}
+
+// PurgeTrashLoop runs PurgeExpiredTrash every TrashPurgeInterval, forever.
+func PurgeTrashLoop() {
+	for {
+		if err := PurgeExpiredTrash(); err != nil {
+			log.Error(err.Error())
+		}
+		time.Sleep(TrashPurgeInterval)
+	}
+}
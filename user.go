@@ -26,6 +26,7 @@ type User struct {
 	DisplayName     string
 	IsAdmin         bool
 	IsBlogAuthor    bool
+	IsReviewer      bool
 	LastVisit       time.Time
 	Balance         int64
 	UsingGravatar   bool
@@ -49,13 +50,17 @@ func (u User) Avatar() string {
 }
 
 func (u User) Gravatar() string {
-	return "https://www.gravatar.com/avatar/" + fmt.Sprintf("%x", md5.Sum([]byte(strings.Trim(strings.ToLower(u.Email), " ")))) + "?s=420&d=" + url.QueryEscape("https://fortkickass.co"+u.Identicon())
+	return "https://www.gravatar.com/avatar/" + fmt.Sprintf("%x", md5.Sum([]byte(strings.Trim(strings.ToLower(u.Email), " ")))) + "?s=420&d=" + url.QueryEscape("https://"+config.Site.Domain+u.Identicon())
 }
 
 func (u User) Identicon() string {
 	return reverse("identicon") + "/" + u.GetInfoHash()
 }
 
+func (u User) ProfileUrl() string {
+	return strings.Join([]string{reverse("author"), u.Username}, "/")
+}
+
 func (u User) GetInfoHash() string {
 	return fmt.Sprintf("%x", md5.Sum([]byte(fmt.Sprintf("%x%x%x", md5.Sum([]byte(u.Email)), md5.Sum([]byte(u.Username)), md5.Sum([]byte(u.Id.Hex()))))))
 }
@@ -118,6 +123,13 @@ func GetUserByName(name string) (u *User, err error) {
 	return
 }
 
+func GetUserByEmail(email string) (u *User, err error) {
+	localsession := session.Copy()
+	defer localsession.Close()
+	err = localsession.DB(database).C("users").Find(bson.M{"email": email}).One(&u)
+	return
+}
+
 func GetAllUsers() []User {
 	localsession := session.Copy()
 	defer localsession.Close()
@@ -8,8 +8,10 @@ import (
 	"golang.org/x/text/unicode/norm"
 	"image"
 	"image/jpeg"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"unicode"
 )
 
@@ -29,6 +31,16 @@ func reverse(name string, things ...interface{}) string {
 	return u.Path
 }
 
+// pageFromRequest returns the 1-based page number in the page query
+// parameter, or 1 if there is none.
+func pageFromRequest(req *http.Request) int {
+	page, err := strconv.Atoi(req.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		return 1
+	}
+	return page
+}
+
 func Slugify(s string) string {
 	buf := make([]rune, 0, len(s))
 	dash := false
@@ -0,0 +1,251 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PopularCount and TrendingCount are how many posts the popular and
+// trending widgets list.
+const (
+	PopularCount  = 5
+	TrendingCount = 5
+)
+
+// Trending posts are scored on the views of the last TrendingDays days,
+// where a view counts half as much every TrendingHalfLife.
+const (
+	TrendingDays     = 7
+	TrendingHalfLife = 48 * time.Hour
+)
+
+// The popular and trending rankings are worked out every
+// ViewRankingInterval rather than on every page view, and keep
+// ViewRankingSize posts so the widgets have spares for posts no longer
+// listed.
+const (
+	ViewRankingInterval = 10 * time.Minute
+	ViewRankingSize     = 20
+)
+
+var (
+	viewRankingLock sync.RWMutex
+	popularIds      []bson.ObjectId
+	trendingIds     []bson.ObjectId
+)
+
+// PostViews is the number of views a post got on one day. Views are kept
+// apart from the post itself so saving a post never loses any.
+type PostViews struct {
+	Post  bson.ObjectId `bson:"_post"`
+	Day   time.Time
+	Count int64
+}
+
+// viewDay is the day bucket a view at t is counted in.
+func viewDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// PostForPath returns the id of the published post a request path reads,
+// whether it names the post by slug or by id.
+func PostForPath(path string) (bson.ObjectId, bool) {
+	var post BlogPost
+	var err error
+	if slug := strings.TrimPrefix(path, reverse("blog-read")+"/"); slug != path {
+		post, err = GetBlogPostWithSlug(slug)
+	} else if id := strings.TrimPrefix(path, reverse("blog-static")+"/"); id != path && bson.IsObjectIdHex(id) {
+		post, err = GetBlogPostWithId(bson.ObjectIdHex(id))
+	} else {
+		return "", false
+	}
+	if err != nil || !post.Published {
+		return "", false
+	}
+	return post.Id, true
+}
+
+// CountPostView counts a view of the post the request reads, if any. Only
+// successful GETs count, so the id URL redirecting to the slug URL is
+// counted once.
+func CountPostView(req *http.Request, response int) {
+	if req.Method != "GET" || response < 200 || response > 299 {
+		return
+	}
+	id, ok := PostForPath(req.URL.Path)
+	if !ok {
+		return
+	}
+	localsession := session.Copy()
+	defer localsession.Close()
+	_, err := localsession.DB(database).C("postviews").Upsert(
+		bson.M{"_post": id, "day": viewDay(time.Now())},
+		bson.M{"$inc": bson.M{"count": 1}},
+	)
+	if err != nil {
+		log.Error(err.Error())
+	}
+}
+
+// Views returns how many times the post has been read.
+func (post BlogPost) Views() int64 {
+	localsession := session.Copy()
+	defer localsession.Close()
+	result := struct{ Views int64 }{}
+	localsession.DB(database).C("postviews").Pipe([]bson.M{
+		{"$match": bson.M{"_post": post.Id}},
+		{"$group": bson.M{"_id": nil, "views": bson.M{"$sum": "$count"}}},
+	}).One(&result)
+	return result.Views
+}
+
+// GetPopularBlogs returns the most read posts of all time, as of the last
+// ranking.
+func GetPopularBlogs(count int) []BlogPost {
+	viewRankingLock.RLock()
+	ids := popularIds
+	viewRankingLock.RUnlock()
+	return blogsInOrder(ids, count)
+}
+
+// GetTrendingBlogs returns the posts read the most lately, favouring the
+// most recent views, as of the last ranking.
+func GetTrendingBlogs(count int) []BlogPost {
+	viewRankingLock.RLock()
+	ids := trendingIds
+	viewRankingLock.RUnlock()
+	return blogsInOrder(ids, count)
+}
+
+// TrendingScore decays count views from day as seen at now.
+func TrendingScore(count int64, day time.Time, now time.Time) float64 {
+	// Views are spread over their day, so age them from its middle.
+	age := now.Sub(day.Add(12 * time.Hour))
+	if age < 0 {
+		age = 0
+	}
+	return float64(count) * math.Exp2(-float64(age)/float64(TrendingHalfLife))
+}
+
+// RankViews works out the popular and trending posts again.
+func RankViews() error {
+	popular, err := rankPopular()
+	if err != nil {
+		return err
+	}
+	trending, err := rankTrending(time.Now())
+	if err != nil {
+		return err
+	}
+	viewRankingLock.Lock()
+	popularIds, trendingIds = popular, trending
+	viewRankingLock.Unlock()
+	return nil
+}
+
+// ViewRankingLoop runs RankViews every ViewRankingInterval, forever.
+func ViewRankingLoop() {
+	for {
+		if err := RankViews(); err != nil {
+			log.Warning(fmt.Sprintf("view ranking: %s", err))
+		}
+		time.Sleep(ViewRankingInterval)
+	}
+}
+
+func rankPopular() ([]bson.ObjectId, error) {
+	localsession := session.Copy()
+	defer localsession.Close()
+	totals := []struct {
+		Id    bson.ObjectId `bson:"_id"`
+		Views int64
+	}{}
+	err := localsession.DB(database).C("postviews").Pipe([]bson.M{
+		{"$group": bson.M{"_id": "$_post", "views": bson.M{"$sum": "$count"}}},
+		{"$sort": bson.M{"views": -1}},
+		{"$limit": ViewRankingSize},
+	}).All(&totals)
+	ids := make([]bson.ObjectId, len(totals))
+	for i, total := range totals {
+		ids[i] = total.Id
+	}
+	return ids, err
+}
+
+func rankTrending(now time.Time) ([]bson.ObjectId, error) {
+	localsession := session.Copy()
+	defer localsession.Close()
+	buckets := []PostViews{}
+	err := localsession.DB(database).C("postviews").Find(bson.M{
+		"day": bson.M{"$gte": viewDay(now).AddDate(0, 0, -TrendingDays)},
+	}).All(&buckets)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make(map[bson.ObjectId]float64)
+	for _, bucket := range buckets {
+		scores[bucket.Post] += TrendingScore(bucket.Count, bucket.Day, now)
+	}
+	ids := make([]bson.ObjectId, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if scores[ids[i]] != scores[ids[j]] {
+			return scores[ids[i]] > scores[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	if len(ids) > ViewRankingSize {
+		ids = ids[:ViewRankingSize]
+	}
+	return ids, nil
+}
+
+// EnsureViewIndexes indexes views by post and day, for counting and
+// totalling the views of a post, and by day alone, for trending posts.
+func EnsureViewIndexes() error {
+	views := session.DB("").C("postviews")
+	if err := views.EnsureIndex(mgo.Index{Key: []string{"_post", "day"}, Unique: true}); err != nil {
+		return err
+	}
+	return views.EnsureIndex(mgo.Index{Key: []string{"day"}})
+}
+
+// blogsInOrder returns up to count of the listed, published posts with the
+// given ids, in the order of ids.
+func blogsInOrder(ids []bson.ObjectId, count int) []BlogPost {
+	if len(ids) == 0 {
+		return nil
+	}
+	localsession := session.Copy()
+	defer localsession.Close()
+	found := []BlogPost{}
+	localsession.DB(database).C("blogs").Find(listed(bson.M{
+		"published": true,
+		"_id":       bson.M{"$in": ids},
+	})).All(&found)
+	byId := make(map[bson.ObjectId]BlogPost, len(found))
+	for _, post := range found {
+		byId[post.Id] = post
+	}
+	blogs := []BlogPost{}
+	for _, id := range ids {
+		if post, ok := byId[id]; ok && len(blogs) < count {
+			blogs = append(blogs, post)
+		}
+	}
+	return blogs
+}
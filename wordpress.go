@@ -0,0 +1,508 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/xml"
+	"errors"
+	"flag"
+	"fmt"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// WordpressUploads is where WordPress keeps attachments, relative to the
+// site root. Everything after it is looked up in the local uploads directory.
+const WordpressUploads = "/wp-content/uploads/"
+
+// WordpressImageFolder is where images used inside imported posts are
+// copied to, keeping their path below the uploads directory.
+const WordpressImageFolder = "./static/img/wordpress/"
+
+var wordpressUploadUrl = regexp.MustCompile(`https?://[^\s"'()<>]*` + regexp.QuoteMeta(WordpressUploads) + `[^\s"'()<>]+`)
+
+func init() {
+	RegisterCommand("wordpress", Command{
+		Usage: "[-dry-run] [-uploads dir] export.xml",
+		Run:   wordpressCommand,
+	})
+}
+
+// wxr is the part of a WordPress eXtended RSS export the importer uses.
+// Elements are matched by local name, so all WXR versions work.
+type wxr struct {
+	Channel struct {
+		Authors []wxrAuthor `xml:"author"`
+		Items   []wxrItem   `xml:"item"`
+	} `xml:"channel"`
+}
+
+type wxrAuthor struct {
+	Login       string `xml:"author_login"`
+	Email       string `xml:"author_email"`
+	DisplayName string `xml:"author_display_name"`
+}
+
+type wxrItem struct {
+	Title     string `xml:"title"`
+	Creator   string `xml:"creator"`
+	PostId    string `xml:"post_id"`
+	PostDate  string `xml:"post_date_gmt"`
+	LocalDate string `xml:"post_date"`
+	Name      string `xml:"post_name"`
+	Status    string `xml:"status"`
+	Type      string `xml:"post_type"`
+	Url       string `xml:"attachment_url"`
+	Encoded   []struct {
+		XMLName xml.Name
+		Value   string `xml:",chardata"`
+	} `xml:"encoded"`
+	Categories []struct {
+		Domain string `xml:"domain,attr"`
+		Name   string `xml:",chardata"`
+	} `xml:"category"`
+	Meta []struct {
+		Key   string `xml:"meta_key"`
+		Value string `xml:"meta_value"`
+	} `xml:"postmeta"`
+	Comments []struct {
+		Id string `xml:"comment_id"`
+	} `xml:"comment"`
+}
+
+// encoded returns the content:encoded or excerpt:encoded element.
+func (item wxrItem) encoded(namespace string) string {
+	for _, e := range item.Encoded {
+		if strings.Contains(e.XMLName.Space, "/"+namespace) {
+			return e.Value
+		}
+	}
+	return ""
+}
+
+func (item wxrItem) meta(key string) string {
+	for _, m := range item.Meta {
+		if m.Key == key {
+			return m.Value
+		}
+	}
+	return ""
+}
+
+func (item wxrItem) date() time.Time {
+	if t, err := time.Parse("2006-01-02 15:04:05", item.PostDate); err == nil {
+		return t
+	}
+	t, _ := time.ParseInLocation("2006-01-02 15:04:05", item.LocalDate, time.Local)
+	return t.UTC()
+}
+
+func (item wxrItem) tags() []string {
+	tags := []string{}
+	for _, c := range item.Categories {
+		if (c.Domain == "post_tag" || c.Domain == "category") && !strings.EqualFold(c.Name, "Uncategorized") {
+			tags = append(tags, c.Name)
+		}
+	}
+	return tags
+}
+
+// wordpressImport holds the state of importing one WXR file.
+type wordpressImport struct {
+	uploads     string
+	dryRun      bool
+	authors     map[string]wxrAuthor
+	users       map[string]bson.ObjectId
+	attachments map[string]string
+}
+
+// localUpload returns the file in the uploads directory for an attachment
+// URL, if there is one.
+func (wp *wordpressImport) localUpload(u string) (string, bool) {
+	i := strings.Index(u, WordpressUploads)
+	if i < 0 || wp.uploads == "" {
+		return "", false
+	}
+	rel := path.Clean("/" + u[i+len(WordpressUploads):])
+	file := filepath.Join(wp.uploads, filepath.FromSlash(rel))
+	if _, err := os.Stat(file); err != nil {
+		return "", false
+	}
+	return file, true
+}
+
+// copyUploads copies the uploads a post's HTML links to into
+// WordpressImageFolder and points the links there.
+func (wp *wordpressImport) copyUploads(content string) string {
+	return wordpressUploadUrl.ReplaceAllStringFunc(content, func(u string) string {
+		file, ok := wp.localUpload(u)
+		if !ok {
+			log.Warning(fmt.Sprintf("wordpress: no local file for %s", u))
+			return u
+		}
+		rel := path.Clean("/" + u[strings.Index(u, WordpressUploads)+len(WordpressUploads):])
+		if !wp.dryRun {
+			data, err := ioutil.ReadFile(file)
+			if err == nil {
+				err = writeFile(filepath.Join(WordpressImageFolder, filepath.FromSlash(rel)), data)
+			}
+			if err != nil {
+				log.Warning(fmt.Sprintf("wordpress: copying %s: %s", file, err))
+				return u
+			}
+		}
+		return "/assets/img/wordpress" + rel
+	})
+}
+
+// freeUsername returns login, or login with the lowest number after it
+// that no user has yet.
+func freeUsername(login string) (string, error) {
+	for n := 1; ; n++ {
+		username := login
+		if n > 1 {
+			username = fmt.Sprintf("%s%d", login, n)
+		}
+		_, err := GetUserByName(username)
+		if err == mgo.ErrNotFound {
+			return username, nil
+		} else if err != nil {
+			return "", err
+		}
+	}
+}
+
+// user returns the User for a WordPress author login, found by email and
+// created as a blog author without a password if there is none.
+func (wp *wordpressImport) user(login string) (bson.ObjectId, error) {
+	if id, ok := wp.users[login]; ok {
+		return id, nil
+	}
+	author, ok := wp.authors[login]
+	if !ok || author.Email == "" {
+		return "", fmt.Errorf("no email for author %q", login)
+	}
+
+	u, err := GetUserByEmail(author.Email)
+	if err == mgo.ErrNotFound {
+		var username string
+		if username, err = freeUsername(author.Login); err != nil {
+			return "", err
+		}
+		u = &User{
+			Id:           bson.NewObjectId(),
+			Username:     username,
+			DisplayName:  author.DisplayName,
+			Email:        author.Email,
+			IsBlogAuthor: true,
+		}
+		if u.DisplayName == "" {
+			u.DisplayName = u.Username
+		}
+		fmt.Printf("%-9s user %s <%s>\n", ImportCreated, u.Username, u.Email)
+		if !wp.dryRun {
+			localsession := session.Copy()
+			defer localsession.Close()
+			err = localsession.DB(database).C("users").Insert(u)
+		} else {
+			err = nil
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	wp.users[login] = u.Id
+	return u.Id, nil
+}
+
+func (wp *wordpressImport) post(item wxrItem) (ImportedPost, error) {
+	in := ImportedPost{}
+	author, err := wp.user(item.Creator)
+	if err != nil {
+		return in, err
+	}
+
+	content := wp.copyUploads(item.encoded("content"))
+	source, ok := HtmlToMarkdown(content)
+	if !ok {
+		source = markdownPolicy.Sanitize(content)
+	}
+
+	in.Title = item.Title
+	in.Slug = item.Name
+	if in.Slug == "" {
+		in.Slug = Slugify(item.Title)
+	}
+	in.Date = item.date()
+	in.Summary = strings.TrimSpace(item.encoded("excerpt"))
+	in.Tags = item.tags()
+	in.Published = item.Status == "publish"
+	in.Source = source
+	in.Author = author
+
+	if thumbnail, ok := wp.attachments[item.meta("_thumbnail_id")]; ok {
+		if file, ok := wp.localUpload(thumbnail); ok {
+			in.ImageName = filepath.Base(file)
+			in.ImageData, err = ioutil.ReadFile(file)
+		}
+	}
+	return in, err
+}
+
+// ImportWordpress imports the posts in a WXR export. Attachments are read
+// from the uploads directory, nothing is downloaded.
+func ImportWordpress(file string, uploads string, dryRun bool) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	export := wxr{}
+	if err = xml.Unmarshal(data, &export); err != nil {
+		return err
+	}
+
+	wp := &wordpressImport{
+		uploads:     uploads,
+		dryRun:      dryRun,
+		authors:     map[string]wxrAuthor{},
+		users:       map[string]bson.ObjectId{},
+		attachments: map[string]string{},
+	}
+	for _, author := range export.Channel.Authors {
+		wp.authors[author.Login] = author
+	}
+	for _, item := range export.Channel.Items {
+		if item.Type == "attachment" {
+			wp.attachments[item.PostId] = item.Url
+		}
+	}
+
+	counts := map[string]int{}
+	comments := 0
+	for _, item := range export.Channel.Items {
+		if item.Type != "post" || item.Status == "trash" || item.Status == "auto-draft" {
+			continue
+		}
+		in, err := wp.post(item)
+		action := ""
+		if err == nil {
+			action, err = in.Upsert(dryRun)
+		}
+		if err != nil {
+			fmt.Printf("%-9s %q: %s\n", "error", item.Title, err)
+			counts["error"]++
+			continue
+		}
+		fmt.Printf("%-9s %q -> %s\n", action, item.Title, in.Slug)
+		counts[action]++
+		comments += len(item.Comments)
+	}
+
+	prefix := ""
+	if dryRun {
+		prefix = "dry run: "
+	}
+	fmt.Printf("%s%d created, %d updated, %d unchanged, %d failed\n", prefix,
+		counts[ImportCreated], counts[ImportUpdated], counts[ImportUnchanged], counts["error"])
+	if comments > 0 {
+		fmt.Printf("%d comments were not imported, this site has no comments\n", comments)
+	}
+	if counts["error"] > 0 {
+		return fmt.Errorf("%d posts could not be imported", counts["error"])
+	}
+	return nil
+}
+
+func wordpressCommand(args []string) error {
+	flags := flag.NewFlagSet("wordpress", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "report what would change without writing anything")
+	uploads := flags.String("uploads", "", "local copy of wp-content/uploads")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: wordpress " + commands["wordpress"].Usage)
+	}
+	return ImportWordpress(flags.Arg(0), *uploads, *dryRun)
+}
+
+var (
+	markdownEscaper = strings.NewReplacer(`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`,
+		`&`, `&amp;`, `<`, `&lt;`, `>`, `&gt;`)
+	blankLines    = regexp.MustCompile(`\n\s*\n`)
+	extraNewlines = regexp.MustCompile(`\n(?:[ \t]*\n){2,}`)
+	// Text starting like a list item, or a heading or rule, would become
+	// one at the start of a line.
+	listNumber   = regexp.MustCompile(`^(\d+)([.)])`)
+	blockMarker  = regexp.MustCompile(`^[-+#]`)
+	backtickRuns = regexp.MustCompile("`+")
+	// Link destinations end at the first unbalanced parenthesis or space.
+	destinationEscaper = strings.NewReplacer("(", "%28", ")", "%29", " ", "%20")
+)
+
+// HtmlToMarkdown converts the HTML of a WordPress post to Markdown. It
+// returns false if the HTML uses elements Markdown can't express.
+func HtmlToMarkdown(src string) (string, bool) {
+	nodes, err := html.ParseFragment(strings.NewReader(src), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		return "", false
+	}
+	c := &htmlConverter{ok: true}
+	var out strings.Builder
+	for _, n := range nodes {
+		out.WriteString(c.convert(n))
+	}
+	md := extraNewlines.ReplaceAllString(out.String(), "\n\n")
+	return strings.TrimSpace(md) + "\n", c.ok
+}
+
+type htmlConverter struct {
+	ok bool
+}
+
+func (c *htmlConverter) children(n *html.Node) string {
+	var out strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		out.WriteString(c.convert(child))
+	}
+	return out.String()
+}
+
+func (c *htmlConverter) convert(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		// WordPress separates paragraphs with blank lines instead of <p>.
+		paragraphs := blankLines.Split(n.Data, -1)
+		for i, p := range paragraphs {
+			paragraphs[i] = markdownEscaper.Replace(strings.Join(strings.Fields(p), " "))
+			// Text that starts a line must not become a heading or a
+			// list.
+			paragraphs[i] = listNumber.ReplaceAllString(paragraphs[i], `$1\$2`)
+			paragraphs[i] = blockMarker.ReplaceAllString(paragraphs[i], `\$0`)
+			if strings.HasPrefix(p, " ") || strings.HasPrefix(p, "\n") {
+				paragraphs[i] = " " + paragraphs[i]
+			}
+			if strings.HasSuffix(p, " ") || strings.HasSuffix(p, "\n") {
+				paragraphs[i] += " "
+			}
+		}
+		return strings.Join(paragraphs, "\n\n")
+	case html.CommentNode:
+		if strings.TrimSpace(n.Data) == "more" {
+			return "\n\n" + MoreMarker + "\n\n"
+		}
+		return ""
+	case html.ElementNode:
+	default:
+		return c.children(n)
+	}
+
+	switch n.DataAtom {
+	case atom.P, atom.Div:
+		return "\n\n" + strings.TrimSpace(c.children(n)) + "\n\n"
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		level := int(n.Data[1] - '0')
+		return "\n\n" + strings.Repeat("#", level) + " " + strings.Join(strings.Fields(c.children(n)), " ") + "\n\n"
+	case atom.Br:
+		return "  \n"
+	case atom.Hr:
+		return "\n\n---\n\n"
+	case atom.Strong, atom.B:
+		return "**" + strings.TrimSpace(c.children(n)) + "**"
+	case atom.Em, atom.I:
+		return "_" + strings.TrimSpace(c.children(n)) + "_"
+	case atom.Span:
+		return c.children(n)
+	case atom.Code:
+		return codeSpan(textContent(n))
+	case atom.Pre:
+		return "\n\n```\n" + strings.Trim(textContent(n), "\n") + "\n```\n\n"
+	case atom.A:
+		text := strings.TrimSpace(c.children(n))
+		href := attr(n, "href")
+		if href == "" {
+			return text
+		}
+		return "[" + text + "](" + destinationEscaper.Replace(href) + ")"
+	case atom.Img:
+		return "![" + markdownEscaper.Replace(attr(n, "alt")) + "](" + destinationEscaper.Replace(attr(n, "src")) + ")"
+	case atom.Blockquote:
+		lines := strings.Split(strings.TrimSpace(c.children(n)), "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight("> "+line, " ")
+		}
+		return "\n\n" + strings.Join(lines, "\n") + "\n\n"
+	case atom.Ul, atom.Ol:
+		var out strings.Builder
+		out.WriteString("\n\n")
+		number := 0
+		for li := n.FirstChild; li != nil; li = li.NextSibling {
+			if li.Type != html.ElementNode || li.DataAtom != atom.Li {
+				continue
+			}
+			number++
+			marker := "- "
+			if n.DataAtom == atom.Ol {
+				marker = fmt.Sprintf("%d. ", number)
+			}
+			item := strings.TrimSpace(extraNewlines.ReplaceAllString(c.children(li), "\n\n"))
+			item = strings.Replace(item, "\n", "\n"+strings.Repeat(" ", len(marker)), -1)
+			out.WriteString(marker + item + "\n")
+		}
+		out.WriteString("\n")
+		return out.String()
+	}
+
+	c.ok = false
+	return c.children(n)
+}
+
+// codeSpan quotes code with more backticks than it contains in a row,
+// padding it so backticks at its ends don't join the quotes.
+func codeSpan(code string) string {
+	longest := 0
+	for _, run := range backtickRuns.FindAllString(code, -1) {
+		if len(run) > longest {
+			longest = len(run)
+		}
+	}
+	if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") {
+		code = " " + code + " "
+	}
+	quote := strings.Repeat("`", longest+1)
+	return quote + code + quote
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var out strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		out.WriteString(textContent(child))
+	}
+	return out.String()
+}
+
+func attr(n *html.Node, name string) string {
+	for _, a := range n.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+	return ""
+}
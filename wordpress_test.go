@@ -0,0 +1,61 @@
+// Copyright (c) 2015 Henry Slawniak <henry@slawniak.com>
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"testing"
+)
+
+func TestHtmlToMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"paragraph", "<p>Hello <strong>world</strong></p>", "Hello **world**\n"},
+		{"markdown characters", "<p>a*b_c [d] `e`</p>", "a\\*b\\_c \\[d\\] \\`e\\`\n"},
+		{"entities", "<p>1 &lt; 2 &amp;&amp; 3 &gt; 2</p>", "1 &lt; 2 &amp;&amp; 3 &gt; 2\n"},
+		{"leading hash", "<p>#1 fan</p>", "\\#1 fan\n"},
+		{"leading number", "<p>1. is not a list</p>", "1\\. is not a list\n"},
+		{"leading number with parenthesis", "<p>2) neither</p>", "2\\) neither\n"},
+		{"leading dash", "<p>- not an item</p>", "\\- not an item\n"},
+		{"leading plus", "<p>+ not an item</p>", "\\+ not an item\n"},
+		{"leading quote", "<p>&gt; not a quote</p>", "&gt; not a quote\n"},
+		{"unwrapped paragraphs", "First\n\n1. second\n\n# third", "First\n\n1\\. second\n\n\\# third\n"},
+		{"code", "<p><code>a &lt; b</code></p>", "`a < b`\n"},
+		{"code with a backtick", "<p><code>a`b</code></p>", "``a`b``\n"},
+		{"code with backtick runs", "<p><code>a``b`c</code></p>", "```a``b`c```\n"},
+		{"code starting with a backtick", "<p><code>`a</code></p>", "`` `a ``\n"},
+		{"link", `<p><a href="https://example.com/a">A</a></p>`, "[A](https://example.com/a)\n"},
+		{"link with parentheses", `<p><a href="https://en.wikipedia.org/wiki/Go_(language)">Go</a></p>`, "[Go](https://en.wikipedia.org/wiki/Go_%28language%29)\n"},
+		{"image with a space", `<img src="/a b.png" alt="x">`, "![x](/a%20b.png)\n"},
+	}
+	for _, test := range tests {
+		got, ok := HtmlToMarkdown(test.html)
+		if !ok || got != test.want {
+			t.Errorf("%s: HtmlToMarkdown(%q) = %q, %v, want %q", test.name, test.html, got, ok, test.want)
+		}
+	}
+}
+
+func TestHtmlToMarkdownRenders(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"leading number", "<p>1. is not a list</p>", "<p>1. is not a list</p>\n"},
+		{"leading dash", "<p>- not an item</p>", "<p>- not an item</p>\n"},
+		{"leading hash", "<p># not a heading</p>", "<p># not a heading</p>\n"},
+		{"leading quote", "<p>&gt; not a quote</p>", "<p>&gt; not a quote</p>\n"},
+		{"code with a backtick", "<p><code>a`b</code></p>", "<p><code>a`b</code></p>\n"},
+		{"link with parentheses", `<p><a href="/wiki/Go_(language)">Go</a></p>`, "<p><a href=\"/wiki/Go_%28language%29\" rel=\"nofollow\">Go</a></p>\n"},
+	}
+	for _, test := range tests {
+		md, _ := HtmlToMarkdown(test.html)
+		if got := string(RenderMarkdown(md)); got != test.want {
+			t.Errorf("%s: %q renders as %q, want %q", test.name, md, got, test.want)
+		}
+	}
+}